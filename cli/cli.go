@@ -15,7 +15,7 @@ import (
 func NewConfigFromCLI() (*config.Config, error, bool) {
 	c := config.NewConfig()
 
-	var path, headers string
+	var path, headers, redisMetricsTags string
 	var helpOrVersionWereShown bool = true
 
 	// Print raw version without prefix
@@ -27,7 +27,7 @@ func NewConfigFromCLI() (*config.Config, error, bool) {
 	flags = append(flags, serverCLIFlags(&c, &path)...)
 	flags = append(flags, sslCLIFlags(&c)...)
 	flags = append(flags, broadcastCLIFlags(&c)...)
-	flags = append(flags, redisCLIFlags(&c)...)
+	flags = append(flags, redisCLIFlags(&c, &redisMetricsTags)...)
 	flags = append(flags, httpBroadcastCLIFlags(&c)...)
 	flags = append(flags, natsCLIFlags(&c)...)
 	flags = append(flags, rpcCLIFlags(&c, &headers)...)
@@ -71,6 +71,16 @@ func NewConfigFromCLI() (*config.Config, error, bool) {
 
 	c.Headers = strings.Split(strings.ToLower(headers), ",")
 
+	if redisMetricsTags != "" {
+		tags, err := parseTags(redisMetricsTags)
+
+		if err != nil {
+			return &config.Config{}, err, false
+		}
+
+		c.Redis.MetricsTags = tags
+	}
+
 	if c.Debug {
 		c.LogLevel = "debug"
 		c.LogFormat = "text"
@@ -92,3 +102,20 @@ Use metrics_rotate_interval instead.`)
 
 	return &c, nil, false
 }
+
+// parseTags parses tags in the "key:value,key2:value2" format
+func parseTags(str string) (map[string]string, error) {
+	tags := make(map[string]string)
+
+	for _, pair := range strings.Split(str, ",") {
+		parts := strings.SplitN(strings.TrimSpace(pair), ":", 2)
+
+		if len(parts) != 2 || parts[0] == "" {
+			return nil, fmt.Errorf("Invalid tag format: %q, expected key:value", pair) //nolint:stylecheck
+		}
+
+		tags[parts[0]] = parts[1]
+	}
+
+	return tags, nil
+}
@@ -95,7 +95,7 @@ func broadcastCLIFlags(c *config.Config) []cli.Flag {
 	return withDefaults(broadcastCategoryDescription, []cli.Flag{
 		&cli.StringFlag{
 			Name:        "broadcast_adapter",
-			Usage:       "Broadcasting adapter to use (redis, http or nats)",
+			Usage:       "Broadcasting adapter to use (redis, redis_queue, http or nats)",
 			Value:       c.BroadcastAdapter,
 			Destination: &c.BroadcastAdapter,
 		},
@@ -109,7 +109,7 @@ func broadcastCLIFlags(c *config.Config) []cli.Flag {
 	})
 }
 
-func redisCLIFlags(c *config.Config) []cli.Flag {
+func redisCLIFlags(c *config.Config, metricsTags *string) []cli.Flag {
 	return withDefaults(redisCategoryDescription, []cli.Flag{
 		&cli.StringFlag{
 			Name:        "redis_url",
@@ -126,12 +126,208 @@ func redisCLIFlags(c *config.Config) []cli.Flag {
 			Destination: &c.Redis.Channel,
 		},
 
+		&cli.IntFlag{
+			Name:        "redis_max_connection_lifetime",
+			Usage:       "Re-establish Redis pub/sub connection after N seconds (0 – never)",
+			Destination: &c.Redis.MaxConnectionLifetime,
+		},
+
+		&cli.BoolFlag{
+			Name:        "redis_single_shot",
+			Usage:       "Do not reconnect to Redis: exit as soon as the subscription is closed or failed",
+			Destination: &c.Redis.SingleShot,
+		},
+
+		&cli.IntFlag{
+			Name:        "redis_max_runtime",
+			Usage:       "Stop the Redis subscriber and exit cleanly after N seconds (0 – never)",
+			Destination: &c.Redis.MaxRuntime,
+		},
+
+		&cli.IntFlag{
+			Name:        "redis_cold_retry_interval",
+			Usage:       "Keep reconnecting to Redis every N seconds after reconnect attempts are exhausted instead of exiting (0 – exit)",
+			Destination: &c.Redis.ColdRetryInterval,
+		},
+
+		&cli.IntFlag{
+			Name:        "redis_warmup_period",
+			Usage:       "Do not count Redis connection failures toward reconnect attempts during N seconds after start (0 – disabled)",
+			Destination: &c.Redis.WarmupPeriod,
+		},
+
+		&cli.StringFlag{
+			Name:        "redis_healthcheck_url",
+			Usage:       "Redis URL to use for health checks (e.g., a read replica); the primary Redis is used if empty",
+			Destination: &c.Redis.HealthcheckURL,
+		},
+
+		&cli.IntFlag{
+			Name:        "redis_pool_max_active",
+			Usage:       "The max number of auxiliary Redis connections (used for commands other than pub/sub; 0 means unlimited)",
+			Value:       c.Redis.PoolMaxActive,
+			Destination: &c.Redis.PoolMaxActive,
+		},
+
+		&cli.IntFlag{
+			Name:        "redis_pool_max_idle",
+			Usage:       "The max number of idle auxiliary Redis connections",
+			Value:       c.Redis.PoolMaxIdle,
+			Destination: &c.Redis.PoolMaxIdle,
+		},
+
+		&cli.IntFlag{
+			Name:        "redis_pool_idle_timeout",
+			Usage:       "Close idle auxiliary Redis connections after this number of seconds (0 means never)",
+			Value:       c.Redis.PoolIdleTimeout,
+			Destination: &c.Redis.PoolIdleTimeout,
+		},
+
+		&cli.BoolFlag{
+			Name:        "redis_systemd_watchdog",
+			Usage:       "Ping systemd watchdog (WatchdogSec=) while Redis subscriber is live, so a wedged subscriber causes a restart",
+			Destination: &c.Redis.SystemdWatchdog,
+		},
+
+		&cli.StringFlag{
+			Name:        "redis_marker_payload",
+			Usage:       "Publish a marker with this prefix to Redis channel after subscribing and consider the subscriber ready only when it comes back (disabled if empty)",
+			Destination: &c.Redis.MarkerPayload,
+		},
+
+		&cli.IntFlag{
+			Name:        "redis_marker_timeout",
+			Usage:       "How long to wait for Redis delivery marker to come back (seconds)",
+			Value:       c.Redis.MarkerTimeout,
+			Destination: &c.Redis.MarkerTimeout,
+		},
+
+		&cli.IntFlag{
+			Name:        "redis_latency_threshold",
+			Usage:       "Warn when the p99 time from receiving a Redis message to handling it exceeds this number of milliseconds (0 means disabled)",
+			Destination: &c.Redis.LatencyThreshold,
+		},
+
+		&cli.IntFlag{
+			Name:        "redis_activity_window",
+			Usage:       "Warn if expected Redis channels receive no messages within this number of seconds (0 means disabled)",
+			Destination: &c.Redis.ActivityWindow,
+		},
+
+		&cli.StringFlag{
+			Name:        "redis_activity_channels",
+			Usage:       "Comma separated list of Redis channels expected to receive messages (the broadcasting channel by default)",
+			Destination: &c.Redis.ActivityChannels,
+		},
+
+		&cli.StringFlag{
+			Name:        "redis_queue_key",
+			Usage:       "Redis list to consume broadcasts from (for redis_queue adapter)",
+			Value:       c.Redis.QueueKey,
+			Destination: &c.Redis.QueueKey,
+		},
+
+		&cli.StringFlag{
+			Name:        "redis_internal_channel",
+			Usage:       "Redis channel for internal commands (e.g., remote disconnects); disabled if empty",
+			Destination: &c.Redis.InternalChannel,
+		},
+
+		&cli.BoolFlag{
+			Name:        "redis_commands_connection",
+			Usage:       "Subscribe to the Redis internal channel via a separate connection, so broadcasts can't delay commands",
+			Destination: &c.Redis.CommandsConnection,
+		},
+
 		&cli.StringFlag{
 			Name:        "redis_sentinels",
 			Usage:       "Comma separated list of sentinel hosts, format: 'hostname:port,..'",
 			Destination: &c.Redis.Sentinels,
 		},
 
+		&cli.IntFlag{
+			Name:        "redis_sentinel_max_addrs",
+			Usage:       "The max number of sentinel addresses (0 means unlimited)",
+			Value:       c.Redis.SentinelMaxAddrs,
+			Destination: &c.Redis.SentinelMaxAddrs,
+		},
+
+		&cli.IntFlag{
+			Name:        "redis_reconnect_stagger_window",
+			Usage:       "Spread Redis reconnects of multiple nodes over this window in seconds using a node-specific delay (0 means disabled)",
+			Destination: &c.Redis.ReconnectStaggerWindow,
+		},
+
+		&cli.StringFlag{
+			Name:        "redis_reconnect_stagger_key",
+			Usage:       "Node identifier to derive the Redis reconnect delay from (hostname by default)",
+			Destination: &c.Redis.ReconnectStaggerKey,
+		},
+
+		&cli.IntFlag{
+			Name:        "redis_subscribe_busy_retries",
+			Usage:       "The number of subscribe retries on the same connection when Redis is loading the dataset or busy (0 means reconnect right away)",
+			Value:       c.Redis.SubscribeBusyRetries,
+			Destination: &c.Redis.SubscribeBusyRetries,
+		},
+
+		&cli.IntFlag{
+			Name:        "redis_subscribe_busy_interval",
+			Usage:       "Interval between subscribe retries when Redis is loading the dataset or busy in seconds",
+			Value:       c.Redis.SubscribeBusyInterval,
+			Destination: &c.Redis.SubscribeBusyInterval,
+		},
+
+		&cli.StringFlag{
+			Name:        "redis_sentinel_fallback_url",
+			Usage:       "Direct Redis URL to use when sentinels are unavailable",
+			Destination: &c.Redis.SentinelFallbackURL,
+		},
+
+		&cli.IntFlag{
+			Name:        "redis_sentinel_fallback_attempts",
+			Usage:       "The number of failed sentinel discovery attempts in a row before falling back to the direct Redis URL",
+			Value:       c.Redis.SentinelFallbackAttempts,
+			Destination: &c.Redis.SentinelFallbackAttempts,
+		},
+
+		&cli.IntFlag{
+			Name:        "redis_sentinel_promotion_wait",
+			Usage:       "How long to wait for the master resolved via sentinels to finish promotion after a failover in milliseconds (0 means no waiting)",
+			Value:       c.Redis.SentinelPromotionWait,
+			Destination: &c.Redis.SentinelPromotionWait,
+		},
+
+		&cli.IntFlag{
+			Name:        "redis_sentinel_min_reachable",
+			Usage:       "The min number of reachable sentinels agreeing on the master address to trust the master discovery (0 means not checked)",
+			Destination: &c.Redis.SentinelMinReachable,
+		},
+
+		&cli.BoolFlag{
+			Name:        "redis_sentinel_log_master",
+			Usage:       "Attach the resolved Redis master address to all the Redis subscriber log entries in sentinel mode",
+			Destination: &c.Redis.SentinelLogMaster,
+		},
+
+		&cli.StringFlag{
+			Name:        "redis_min_version",
+			Usage:       "Minimum required Redis server version (e.g., 6.0); fail to start if the server is older",
+			Destination: &c.Redis.MinVersion,
+		},
+
+		&cli.BoolFlag{
+			Name:        "redis_sharded",
+			Usage:       "Use Redis 7 sharded pub/sub (SSUBSCRIBE) in cluster mode",
+			Destination: &c.Redis.Sharded,
+		},
+
+		&cli.BoolFlag{
+			Name:        "redis_sentinel_replica",
+			Usage:       "Subscribe via a sentinel-resolved replica (only if your Redis propagates pub/sub to replicas)",
+			Destination: &c.Redis.SentinelReplica,
+		},
+
 		&cli.IntFlag{
 			Name:        "redis_sentinel_discovery_interval",
 			Usage:       "Interval to rediscover sentinels in seconds",
@@ -145,6 +341,330 @@ func redisCLIFlags(c *config.Config) []cli.Flag {
 			Value:       c.Redis.KeepalivePingInterval,
 			Destination: &c.Redis.KeepalivePingInterval,
 		},
+
+		&cli.IntFlag{
+			Name:        "redis_command_timeout",
+			Usage:       "Timeout for Redis connect, command writes and subscription confirmations (seconds, 0 to disable)",
+			Value:       c.Redis.CommandTimeout,
+			Destination: &c.Redis.CommandTimeout,
+		},
+
+		&cli.StringFlag{
+			Name:        "redis_keepalive_mode",
+			Usage:       "How to keep Redis subscription connection alive: ping, subscribe-noop or none (rely on TCP keepalive)",
+			Value:       c.Redis.KeepaliveMode,
+			Destination: &c.Redis.KeepaliveMode,
+		},
+
+		&cli.BoolFlag{
+			Name:        "redis_wait_ready",
+			Usage:       "Subscribe to Redis channel only after the server is fully initialized",
+			Destination: &c.Redis.WaitReady,
+		},
+
+		&cli.BoolFlag{
+			Name:        "redis_tcp_keepalive",
+			Usage:       "Enable TCP keepalive for Redis connections",
+			Destination: &c.Redis.TCPKeepalive,
+		},
+
+		&cli.IntFlag{
+			Name:        "redis_tcp_keepalive_interval",
+			Usage:       "TCP keepalive interval for Redis connections in seconds",
+			Value:       c.Redis.TCPKeepaliveInterval,
+			Destination: &c.Redis.TCPKeepaliveInterval,
+		},
+
+		&cli.IntFlag{
+			Name:        "redis_read_buffer_size",
+			Usage:       "Redis connections read buffer size in bytes (0 – use the Redis client default)",
+			Destination: &c.Redis.ReadBufferSize,
+		},
+
+		&cli.StringFlag{
+			Name:        "redis_dead_letter_key",
+			Usage:       "Redis list to store dropped broadcast messages for inspection (disabled if empty)",
+			Destination: &c.Redis.DeadLetterKey,
+		},
+
+		&cli.IntFlag{
+			Name:        "redis_dead_letter_max_len",
+			Usage:       "The max number of messages to keep in the dead letter list",
+			Value:       c.Redis.DeadLetterMaxLen,
+			Destination: &c.Redis.DeadLetterMaxLen,
+		},
+
+		&cli.StringFlag{
+			Name:        "redis_error_rules",
+			Usage:       "Comma-separated list of Redis connection error substrings with reconnect actions (e.g., \"max number of clients=retry-slow\"); actions: retry-fast, retry-slow, fatal",
+			Destination: &c.Redis.ErrorRules,
+		},
+
+		&cli.StringFlag{
+			Name:        "redis_poll_key",
+			Usage:       "Redis list to poll for broadcasts when pub/sub is unavailable after all reconnect attempts (disabled if empty)",
+			Destination: &c.Redis.PollKey,
+		},
+
+		&cli.StringFlag{
+			Name:        "redis_poll_url",
+			Usage:       "Redis URL to poll the broadcasts list from (e.g., a replica); the primary Redis is used if empty",
+			Destination: &c.Redis.PollURL,
+		},
+
+		&cli.IntFlag{
+			Name:        "redis_poll_interval",
+			Usage:       "Redis broadcasts list polling interval (seconds)",
+			Value:       c.Redis.PollInterval,
+			Destination: &c.Redis.PollInterval,
+		},
+
+		&cli.IntFlag{
+			Name:        "redis_poll_size",
+			Usage:       "The max number of the most recent broadcasts to read from Redis list on every poll",
+			Value:       c.Redis.PollSize,
+			Destination: &c.Redis.PollSize,
+		},
+
+		&cli.StringFlag{
+			Name:        "redis_replay_key",
+			Usage:       "Redis list with recent broadcasts to replay on every (re)connect before live messages (disabled if empty)",
+			Destination: &c.Redis.ReplayKey,
+		},
+
+		&cli.IntFlag{
+			Name:        "redis_replay_size",
+			Usage:       "The max number of recent broadcasts to replay",
+			Value:       c.Redis.ReplaySize,
+			Destination: &c.Redis.ReplaySize,
+		},
+
+		&cli.BoolFlag{
+			Name:        "redis_replay_ordered",
+			Usage:       "Replay recent broadcasts after subscribing and hold live broadcasts until the replay is completed",
+			Destination: &c.Redis.ReplayOrdered,
+		},
+
+		&cli.IntFlag{
+			Name:        "redis_replay_staging_size",
+			Usage:       "The max number of live broadcasts to hold during the ordered replay",
+			Value:       c.Redis.ReplayStagingSize,
+			Destination: &c.Redis.ReplayStagingSize,
+		},
+
+		&cli.BoolFlag{
+			Name:        "redis_tls_verify",
+			Usage:       "Verify Redis server TLS certificate",
+			Destination: &c.Redis.TLSVerify,
+		},
+
+		&cli.BoolFlag{
+			Name:        "redis_tls_strict",
+			Usage:       "Refuse to start if Redis URL doesn't use TLS (rediss://)",
+			Destination: &c.Redis.TLSStrict,
+		},
+
+		&cli.StringFlag{
+			Name:        "redis_crash_dump_path",
+			Usage:       "Debug: file to dump the broadcast caused panic and recent broadcasts to",
+			Destination: &c.Redis.CrashDumpPath,
+		},
+
+		&cli.Int64Flag{
+			Name:        "redis_memory_budget",
+			Usage:       "Memory budget for Redis subscriber buffers in bytes (0 means unlimited)",
+			Destination: &c.Redis.MemoryBudget,
+		},
+
+		&cli.Float64Flag{
+			Name:        "redis_node_pressure_threshold",
+			Usage:       "Apply the dispatch overflow policy when the node pressure reaches this value (from 0 to 1; 0 means disabled)",
+			Destination: &c.Redis.NodePressureThreshold,
+		},
+
+		&cli.StringFlag{
+			Name:        "redis_tee_path",
+			Usage:       "Debug: file to append all received Redis messages to (as JSON lines)",
+			Destination: &c.Redis.TeePath,
+		},
+
+		&cli.StringFlag{
+			Name:        "redis_tee_socket",
+			Usage:       "Unix socket to write all received Redis messages to (length-prefixed channel and payload) for sidecars",
+			Destination: &c.Redis.TeeSocket,
+		},
+
+		&cli.BoolFlag{
+			Name:        "redis_tap_connection",
+			Usage:       "Use a separate Redis connection per channel tap instead of the subscription connection",
+			Destination: &c.Redis.TapConnection,
+		},
+
+		&cli.Int64Flag{
+			Name:        "redis_crash_dump_max_size",
+			Usage:       "Debug: max crash dump file size in bytes",
+			Value:       c.Redis.CrashDumpMaxSize,
+			Destination: &c.Redis.CrashDumpMaxSize,
+		},
+
+		&cli.BoolFlag{
+			Name:        "redis_envelope",
+			Usage:       "Parse broadcast envelopes (stream, epoch, payload) and drop duplicate and out-of-order messages",
+			Destination: &c.Redis.Envelope,
+		},
+
+		&cli.IntFlag{
+			Name:        "redis_envelope_streams_limit",
+			Usage:       "The max number of streams to track epochs for",
+			Value:       c.Redis.EnvelopeStreamsLimit,
+			Destination: &c.Redis.EnvelopeStreamsLimit,
+		},
+
+		&cli.StringFlag{
+			Name:        "redis_keyspace_events",
+			Usage:       "Comma-separated list of Redis keyspace events (e.g., expired,del) to broadcast to the streams named after the affected keys",
+			Destination: &c.Redis.KeyspaceEvents,
+		},
+
+		&cli.StringFlag{
+			Name:        "redis_channels_file",
+			Usage:       "Path to a file with additional Redis channels to subscribe to (one per line), the file is watched for changes",
+			Destination: &c.Redis.ChannelsFile,
+		},
+
+		&cli.StringFlag{
+			Name:        "redis_local_addr",
+			Usage:       "Local address (IP or IP:port) to bind Redis and sentinel connections to",
+			Destination: &c.Redis.LocalAddr,
+		},
+
+		&cli.StringFlag{
+			Name:        "redis_client_name",
+			Usage:       "Redis connections name prefix (CLIENT SETNAME); the subscription connection name includes the subscribed channels",
+			Destination: &c.Redis.ClientName,
+		},
+
+		&cli.StringFlag{
+			Name:        "redis_dedup_key",
+			Usage:       "Broadcast payload field containing a message ID to skip duplicate messages by",
+			Destination: &c.Redis.DedupKey,
+		},
+
+		&cli.IntFlag{
+			Name:        "redis_dedup_cache_size",
+			Usage:       "The max number of recent message IDs to remember for deduplication",
+			Value:       c.Redis.DedupCacheSize,
+			Destination: &c.Redis.DedupCacheSize,
+		},
+
+		&cli.IntFlag{
+			Name:        "redis_dedup_ttl",
+			Usage:       "For how long to remember message IDs for deduplication (seconds)",
+			Value:       c.Redis.DedupTTL,
+			Destination: &c.Redis.DedupTTL,
+		},
+
+		&cli.IntFlag{
+			Name:        "redis_coalesce_window",
+			Usage:       "Suppress broadcasts identical to the previous one on the same channel within this window in milliseconds (0 means disabled)",
+			Destination: &c.Redis.CoalesceWindow,
+		},
+
+		&cli.StringFlag{
+			Name:        "redis_max_age_key",
+			Usage:       "Broadcast payload field containing the broadcast time (Unix timestamp or RFC 3339) to drop stale messages by",
+			Destination: &c.Redis.MaxAgeKey,
+		},
+
+		&cli.IntFlag{
+			Name:        "redis_max_age",
+			Usage:       "Drop messages broadcasted longer than this ago in seconds (0 means disabled)",
+			Destination: &c.Redis.MaxAge,
+		},
+
+		&cli.StringFlag{
+			Name:        "redis_pause_mode",
+			Usage:       "What to do with Redis messages while delivery is paused: block or drop",
+			Value:       c.Redis.PauseMode,
+			Destination: &c.Redis.PauseMode,
+		},
+
+		&cli.StringFlag{
+			Name:        "redis_dispatch_overflow_policy",
+			Usage:       "What to do when the Redis messages dispatch queue is full: block, drop_new or drop_oldest",
+			Value:       c.Redis.DispatchOverflowPolicy,
+			Destination: &c.Redis.DispatchOverflowPolicy,
+		},
+
+		&cli.StringFlag{
+			Name:        "redis_metrics_tags",
+			Usage:       "Comma-separated list of static tags to attach to Redis subscriber metrics, format: 'key:value,..'",
+			Destination: metricsTags,
+		},
+
+		&cli.BoolFlag{
+			Name:        "redis_metrics_connection_tag",
+			Usage:       "Attach the connection tag (Redis host, port and database) to Redis received messages and bytes metrics",
+			Destination: &c.Redis.MetricsConnectionTag,
+		},
+
+		&cli.StringFlag{
+			Name:        "redis_log_context_key",
+			Usage:       "Logger field key to use for the Redis subscriber context",
+			Value:       c.Redis.LogContextKey,
+			Destination: &c.Redis.LogContextKey,
+		},
+
+		&cli.StringFlag{
+			Name:        "redis_log_context",
+			Usage:       "Logger field value to use for the Redis subscriber context",
+			Value:       c.Redis.LogContext,
+			Destination: &c.Redis.LogContext,
+		},
+
+		&cli.StringFlag{
+			Name:        "redis_correlation_id_key",
+			Usage:       "Broadcast payload field containing a correlation ID to attach to per-message logs (disabled if empty)",
+			Destination: &c.Redis.CorrelationIDKey,
+		},
+
+		&cli.IntFlag{
+			Name:        "redis_log_sample_rate",
+			Usage:       "Log every N-th received Redis message at the info level (0 means disabled)",
+			Destination: &c.Redis.LogSampleRate,
+		},
+
+		&cli.IntFlag{
+			Name:        "redis_log_sample_interval",
+			Usage:       "Log at most one received Redis message per interval (seconds) at the info level (0 means disabled)",
+			Destination: &c.Redis.LogSampleInterval,
+		},
+
+		&cli.IntFlag{
+			Name:        "redis_log_sample_max_size",
+			Usage:       "The max size of sampled Redis messages to log in bytes (longer payloads are truncated)",
+			Value:       c.Redis.LogSampleMaxSize,
+			Destination: &c.Redis.LogSampleMaxSize,
+		},
+
+		&cli.IntFlag{
+			Name:        "redis_dispatch_workers",
+			Usage:       "The number of goroutines to dispatch Redis messages (0 – the number of CPUs but at most 4)",
+			Destination: &c.Redis.DispatchWorkers,
+		},
+
+		&cli.IntFlag{
+			Name:        "redis_dispatch_retries",
+			Usage:       "The max number of times to re-attempt dispatching a Redis message after a retryable node error (0 means no retries)",
+			Destination: &c.Redis.DispatchRetries,
+		},
+
+		&cli.IntFlag{
+			Name:        "redis_dispatch_retry_delay",
+			Usage:       "The delay between Redis message dispatch attempts in milliseconds",
+			Value:       c.Redis.DispatchRetryDelay,
+			Destination: &c.Redis.DispatchRetryDelay,
+		},
 	})
 }
 
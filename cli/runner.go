@@ -33,7 +33,7 @@ import (
 
 type controllerFactory = func(*metrics.Metrics, *config.Config) (node.Controller, error)
 type disconnectorFactory = func(*node.Node, *config.Config) (node.Disconnector, error)
-type subscriberFactory = func(pubsub.Handler, *config.Config) (pubsub.Subscriber, error)
+type subscriberFactory = func(pubsub.Handler, *metrics.Metrics, *config.Config) (pubsub.Subscriber, error)
 type websocketHandler = func(*node.Node, *config.Config) (http.Handler, error)
 
 type Shutdownable interface {
@@ -147,7 +147,7 @@ func (r *Runner) Run() error {
 	go disconnector.Run() // nolint:errcheck
 	appNode.SetDisconnector(disconnector)
 
-	subscriber, err := r.subscriberFactory(appNode, r.config)
+	subscriber, err := r.subscriberFactory(appNode, metrics, r.config)
 	if err != nil {
 		return errorx.Decorate(err, "couldn't configure pub/sub")
 	}
@@ -183,6 +183,10 @@ func (r *Runner) Run() error {
 	go r.startWSServer(wsServer)
 	go r.startMetrics(metrics)
 
+	appNode.MarkReady()
+
+	// Shutdown order matters: the subscriber must be stopped before the node,
+	// so no broadcasts are delivered to the node while it's shutting down
 	r.shutdownables = []Shutdownable{
 		metrics,
 		subscriber,
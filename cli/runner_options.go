@@ -51,7 +51,7 @@ func WithSubscriber(fn subscriberFactory) Option {
 
 // WithDefaultSubscriber is an Option to set Runner subscriber to pubsub.NewSubscriber
 func WithDefaultSubscriber() Option {
-	return WithSubscriber(func(h pubsub.Handler, c *config.Config) (pubsub.Subscriber, error) {
-		return pubsub.NewSubscriber(h, c.BroadcastAdapter, &c.Redis, &c.HTTPPubSub, &c.NATSPubSub)
+	return WithSubscriber(func(h pubsub.Handler, m *metrics.Metrics, c *config.Config) (pubsub.Subscriber, error) {
+		return pubsub.NewSubscriber(h, m, c.BroadcastAdapter, &c.Redis, &c.HTTPPubSub, &c.NATSPubSub)
 	})
 }
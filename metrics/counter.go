@@ -7,6 +7,7 @@ import "sync/atomic"
 type Counter struct {
 	name              string
 	desc              string
+	tags              map[string]string
 	value             uint64
 	lastIntervalValue uint64
 	lastIntervalDelta uint64
@@ -27,6 +28,11 @@ func (c *Counter) Desc() string {
 	return c.desc
 }
 
+// Tags returns counter static tags (labels)
+func (c *Counter) Tags() map[string]string {
+	return c.tags
+}
+
 // Value allows to get raw counter value.
 func (c *Counter) Value() uint64 {
 	return atomic.LoadUint64(&c.value)
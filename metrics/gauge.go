@@ -9,6 +9,7 @@ type Gauge struct {
 	value uint64
 	name  string
 	desc  string
+	tags  map[string]string
 }
 
 // NewGauge initializes Gauge.
@@ -26,6 +27,11 @@ func (g *Gauge) Desc() string {
 	return g.desc
 }
 
+// Tags returns gauge static tags (labels)
+func (g *Gauge) Tags() map[string]string {
+	return g.tags
+}
+
 // Set gauge value
 func (g *Gauge) Set(value int) {
 	atomic.StoreUint64(&g.value, uint64(value))
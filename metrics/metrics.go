@@ -30,6 +30,13 @@ type Instrumenter interface {
 	RegisterGauge(name string, desc string)
 }
 
+// TaggedInstrumenter could be implemented by instrumenters supporting
+// static tags (labels) attached to metrics
+type TaggedInstrumenter interface {
+	RegisterTaggedCounter(name string, desc string, tags map[string]string)
+	RegisterTaggedGauge(name string, desc string, tags map[string]string)
+}
+
 // Metrics stores some useful stats about node
 type Metrics struct {
 	mu             sync.RWMutex
@@ -38,12 +45,14 @@ type Metrics struct {
 	httpPath       string
 	rotateInterval time.Duration
 	counters       map[string]*Counter
+	counterNames   map[string]*Counter
 	gauges         map[string]*Gauge
 	shutdownCh     chan struct{}
 	log            *log.Entry
 }
 
 var _ Instrumenter = (*Metrics)(nil)
+var _ TaggedInstrumenter = (*Metrics)(nil)
 
 // NewFromConfig creates a new metrics instance from the prodived configuration
 func NewFromConfig(config *Config) (*Metrics, error) {
@@ -99,6 +108,7 @@ func NewMetrics(writers []IntervalWriter, rotateIntervalSeconds int) *Metrics {
 		writers:        writers,
 		rotateInterval: rotateInterval,
 		counters:       make(map[string]*Counter),
+		counterNames:   make(map[string]*Counter),
 		gauges:         make(map[string]*Gauge),
 		shutdownCh:     make(chan struct{}),
 		log:            log.WithField("context", "metrics"),
@@ -192,6 +202,36 @@ func (m *Metrics) RegisterGauge(name string, desc string) {
 	m.gauges[name] = NewGauge(name, desc)
 }
 
+// RegisterTaggedCounter adds new counter with static tags to the registry.
+// Counters with the same name and different tags are registered as separate series:
+// use SeriesKey to refer to a specific one (the name refers to the latest registered series).
+func (m *Metrics) RegisterTaggedCounter(name string, desc string, tags map[string]string) {
+	counter := NewCounter(name, desc)
+	counter.tags = tags
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.counters[SeriesKey(name, tags)] = counter
+	m.counterNames[name] = counter
+}
+
+// SeriesKey returns the registry key for the metric with static tags
+func SeriesKey(name string, tags map[string]string) string {
+	return name + prometheusLabels(tags)
+}
+
+// RegisterTaggedGauge adds new gauge with static tags to the registry
+func (m *Metrics) RegisterTaggedGauge(name string, desc string, tags map[string]string) {
+	gauge := NewGauge(name, desc)
+	gauge.tags = tags
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.gauges[name] = gauge
+}
+
 // GaugeIncrement increments the given gauge
 func (m *Metrics) GaugeIncrement(name string) {
 	m.gauges[name].Inc()
@@ -207,19 +247,23 @@ func (m *Metrics) GaugeSet(name string, val uint64) {
 	m.gauges[name].Set64(val)
 }
 
-// Counter returns counter by name
+// Counter returns counter by name (or series key)
 func (m *Metrics) Counter(name string) *Counter {
-	return m.counters[name]
+	if counter, ok := m.counters[name]; ok {
+		return counter
+	}
+
+	return m.counterNames[name]
 }
 
 // CounterIncrement increments the given counter
 func (m *Metrics) CounterIncrement(name string) {
-	m.counters[name].Inc()
+	m.Counter(name).Inc()
 }
 
 // CounterAdd adds a value to the given counter
 func (m *Metrics) CounterAdd(name string, val uint64) {
-	m.counters[name].Add(val)
+	m.Counter(name).Add(val)
 }
 
 // EachCounter applies function f(*Gauge) to each gauge in a set
@@ -254,8 +298,9 @@ func (m *Metrics) IntervalSnapshot() map[string]uint64 {
 
 	snapshot := make(map[string]uint64)
 
-	for name, c := range m.counters {
-		snapshot[name] = c.IntervalValue()
+	// Series of the same counter are summed up
+	for _, c := range m.counters {
+		snapshot[c.Name()] += c.IntervalValue()
 	}
 
 	for name, g := range m.gauges {
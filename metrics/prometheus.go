@@ -3,6 +3,7 @@ package metrics
 import (
 	"fmt"
 	"net/http"
+	"sort"
 	"strconv"
 	"strings"
 )
@@ -15,16 +16,30 @@ const (
 func (m *Metrics) Prometheus() string {
 	var buf strings.Builder
 
+	var counters []*Counter
+
 	m.EachCounter(func(counter *Counter) {
-		name := prometheusNamespace + `_` + counter.Name()
+		counters = append(counters, counter)
+	})
 
-		buf.WriteString(
-			"\n# HELP " + name + " " + counter.Desc() + "\n",
-		)
-		buf.WriteString("# TYPE " + name + " counter\n")
-		buf.WriteString(name + " " + strconv.FormatUint(counter.Value(), 10) + "\n")
+	// Series of the same counter must be grouped under a single HELP and TYPE
+	sort.Slice(counters, func(i, j int) bool {
+		return SeriesKey(counters[i].Name(), counters[i].Tags()) < SeriesKey(counters[j].Name(), counters[j].Tags())
 	})
 
+	for i, counter := range counters {
+		name := prometheusNamespace + `_` + counter.Name()
+
+		if i == 0 || counters[i-1].Name() != counter.Name() {
+			buf.WriteString(
+				"\n# HELP " + name + " " + counter.Desc() + "\n",
+			)
+			buf.WriteString("# TYPE " + name + " counter\n")
+		}
+
+		buf.WriteString(name + prometheusLabels(counter.Tags()) + " " + strconv.FormatUint(counter.Value(), 10) + "\n")
+	}
+
 	m.EachGauge(func(gauge *Gauge) {
 		name := prometheusNamespace + `_` + gauge.Name()
 
@@ -32,12 +47,34 @@ func (m *Metrics) Prometheus() string {
 			"\n# HELP " + name + " " + gauge.Desc() + "\n",
 		)
 		buf.WriteString("# TYPE " + name + " gauge\n")
-		buf.WriteString(name + " " + strconv.FormatUint(gauge.Value(), 10) + "\n")
+		buf.WriteString(name + prometheusLabels(gauge.Tags()) + " " + strconv.FormatUint(gauge.Value(), 10) + "\n")
 	})
 
 	return buf.String()
 }
 
+func prometheusLabels(tags map[string]string) string {
+	if len(tags) == 0 {
+		return ""
+	}
+
+	keys := make([]string, 0, len(tags))
+
+	for key := range tags {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	labels := make([]string, 0, len(keys))
+
+	for _, key := range keys {
+		labels = append(labels, key+"="+strconv.Quote(tags[key]))
+	}
+
+	return "{" + strings.Join(labels, ",") + "}"
+}
+
 // PrometheusHandler is provide metrics to the world
 func (m *Metrics) PrometheusHandler(w http.ResponseWriter, r *http.Request) {
 	metricsData := m.Prometheus()
@@ -3,6 +3,7 @@ package metrics
 import (
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -79,3 +80,35 @@ func TestPrometheusHandler(t *testing.T) {
 	assert.Contains(t, body, "anycable_go_test_total 3")
 	assert.Contains(t, body, "anycable_go_any_total 0")
 }
+
+func TestPrometheusWithTags(t *testing.T) {
+	m := NewMetrics(nil, 10)
+
+	m.RegisterTaggedCounter("test_total", "Total number of smth", map[string]string{"region": "eu", "env": "production"})
+	m.RegisterTaggedGauge("tests", "Number of active smth", map[string]string{"env": "production"})
+
+	m.Counter("test_total").Add(3)
+
+	body := m.Prometheus()
+
+	assert.Contains(t, body, "anycable_go_test_total{env=\"production\",region=\"eu\"} 3")
+	assert.Contains(t, body, "anycable_go_tests{env=\"production\"} 0")
+}
+
+func TestPrometheusWithTaggedSeries(t *testing.T) {
+	m := NewMetrics(nil, 10)
+
+	m.RegisterTaggedCounter("test_total", "Total number of smth", map[string]string{"region": "eu"})
+	m.RegisterTaggedCounter("test_total", "Total number of smth", map[string]string{"region": "us"})
+
+	m.CounterAdd(SeriesKey("test_total", map[string]string{"region": "eu"}), 3)
+	m.CounterAdd(SeriesKey("test_total", map[string]string{"region": "us"}), 2)
+
+	body := m.Prometheus()
+
+	assert.Contains(t, body, "anycable_go_test_total{region=\"eu\"} 3")
+	assert.Contains(t, body, "anycable_go_test_total{region=\"us\"} 2")
+	assert.Equal(t, 1, strings.Count(body, "# TYPE anycable_go_test_total counter"))
+
+	assert.Equal(t, uint64(5), m.IntervalSnapshot()["test_total"])
+}
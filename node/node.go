@@ -1,6 +1,7 @@
 package node
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"runtime"
@@ -70,6 +71,8 @@ type Node struct {
 	shutdownCh   chan struct{}
 	shutdownMu   sync.Mutex
 	closed       bool
+	readyCh      chan struct{}
+	readyOnce    sync.Once
 	log          *log.Entry
 }
 
@@ -82,6 +85,7 @@ func NewNode(controller Controller, metrics *metrics.Metrics, config *Config) *N
 		config:     config,
 		controller: controller,
 		shutdownCh: make(chan struct{}),
+		readyCh:    make(chan struct{}),
 		log:        log.WithFields(log.Fields{"context": "node"}),
 	}
 
@@ -102,6 +106,16 @@ func (n *Node) Start() error {
 	return nil
 }
 
+// MarkReady notifies that the node is ready to accept clients and deliver broadcasts
+func (n *Node) MarkReady() {
+	n.readyOnce.Do(func() { close(n.readyCh) })
+}
+
+// Ready returns a channel which is closed as soon as the node is ready
+func (n *Node) Ready() <-chan struct{} {
+	return n.readyCh
+}
+
 // SetDisconnector set disconnector for the node
 func (n *Node) SetDisconnector(d Disconnector) {
 	n.disconnector = d
@@ -127,11 +141,23 @@ func (n *Node) HandleCommand(s *Session, msg *common.Message) (err error) {
 
 // HandlePubSub parses incoming pubsub message and broadcast it
 func (n *Node) HandlePubSub(raw []byte) {
+	n.HandlePubSubWithMeta("", raw, time.Now())
+}
+
+// HandlePubSubWithMeta parses incoming pubsub message and broadcast it.
+// The source channel (if known) and the time the message was received by subscriber are provided as well.
+func (n *Node) HandlePubSubWithMeta(channel string, raw []byte, receivedAt time.Time) {
 	msg, err := common.PubSubMessageFromJSON(raw)
 
 	if err != nil {
 		n.metrics.CounterIncrement(metricsUnknownBroadcast)
-		n.log.Warnf("Failed to parse pubsub message '%s' with error: %v", raw, err)
+
+		if channel != "" {
+			n.log.Warnf("Failed to parse pubsub message '%s' from channel %s with error: %v", raw, channel, err)
+		} else {
+			n.log.Warnf("Failed to parse pubsub message '%s' with error: %v", raw, err)
+		}
+
 		return
 	}
 
@@ -143,6 +169,44 @@ func (n *Node) HandlePubSub(raw []byte) {
 	}
 }
 
+// keyspaceEvent is a Redis keyspace notification payload broadcasted to clients
+type keyspaceEvent struct {
+	Event string `json:"event"`
+	Key   string `json:"key"`
+}
+
+// HandleKeyspaceEvent broadcasts the Redis keyspace notification to the stream named after the affected key
+// (e.g., to let clients know that the record they're watching has expired)
+func (n *Node) HandleKeyspaceEvent(event string, key string) {
+	data, err := json.Marshal(keyspaceEvent{Event: event, Key: key})
+
+	if err != nil {
+		return
+	}
+
+	n.Broadcast(&common.StreamMessage{Stream: key, Data: string(data)})
+}
+
+// HandlePubSubCommand parses incoming pubsub control message and executes it.
+// Unlike HandlePubSub, it doesn't accept broadcast (stream) messages
+func (n *Node) HandlePubSubCommand(raw []byte) {
+	msg, err := common.PubSubMessageFromJSON(raw)
+
+	if err != nil {
+		n.metrics.CounterIncrement(metricsUnknownBroadcast)
+		n.log.Warnf("Failed to parse pubsub command '%s' with error: %v", raw, err)
+		return
+	}
+
+	switch v := msg.(type) {
+	case common.RemoteDisconnectMessage:
+		n.RemoteDisconnect(&v)
+	default:
+		n.metrics.CounterIncrement(metricsUnknownBroadcast)
+		n.log.Warnf("Unsupported pubsub command: %s", raw)
+	}
+}
+
 func (n *Node) LookupSession(id string) *Session {
 	return n.hub.findByIdentifier(id)
 }
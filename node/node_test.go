@@ -2,6 +2,7 @@ package node
 
 import (
 	"testing"
+	"time"
 
 	"github.com/anycable/anycable-go/common"
 	"github.com/stretchr/testify/assert"
@@ -310,6 +311,46 @@ func TestHandlePubSub(t *testing.T) {
 	assert.Equalf(t, expected, string(msg2), "Expected to receive %s but got %s", expected, string(msg2))
 }
 
+func TestHandlePubSubWithMeta(t *testing.T) {
+	node := NewMockNode()
+
+	go node.hub.Run()
+	defer node.hub.Shutdown()
+
+	session := NewMockSession("14", node)
+
+	node.hub.addSession(session)
+	node.hub.subscribeSession("14", "test", "test_channel")
+
+	node.HandlePubSubWithMeta("__anycable__", []byte("{\"stream\":\"test\",\"data\":\"\\\"abc123\\\"\"}"), time.Now())
+
+	expected := "{\"identifier\":\"test_channel\",\"message\":\"abc123\"}"
+
+	msg, err := session.conn.Read()
+	assert.Nil(t, err)
+	assert.Equal(t, expected, string(msg))
+}
+
+func TestHandleKeyspaceEvent(t *testing.T) {
+	node := NewMockNode()
+
+	go node.hub.Run()
+	defer node.hub.Shutdown()
+
+	session := NewMockSession("14", node)
+
+	node.hub.addSession(session)
+	node.hub.subscribeSession("14", "session:42", "test_channel")
+
+	node.HandleKeyspaceEvent("expired", "session:42")
+
+	expected := "{\"identifier\":\"test_channel\",\"message\":{\"event\":\"expired\",\"key\":\"session:42\"}}"
+
+	msg, err := session.conn.Read()
+	assert.Nil(t, err)
+	assert.Equal(t, expected, string(msg))
+}
+
 func TestHandlePubSubWithCommand(t *testing.T) {
 	node := NewMockNode()
 
@@ -329,6 +370,46 @@ func TestHandlePubSubWithCommand(t *testing.T) {
 	assert.True(t, session.closed)
 }
 
+func TestHandlePubSubCommand(t *testing.T) {
+	node := NewMockNode()
+
+	go node.hub.Run()
+	defer node.hub.Shutdown()
+
+	session := NewMockSession("14", node)
+	node.hub.addSession(session)
+	node.hub.subscribeSession("14", "test", "test_channel")
+
+	node.HandlePubSubCommand([]byte("{\"stream\":\"test\",\"data\":\"\\\"abc123\\\"\"}"))
+	node.HandlePubSubCommand([]byte("{\"command\":\"disconnect\",\"payload\":{\"identifier\":\"14\",\"reconnect\":false}}"))
+
+	expected := string(toJSON(newDisconnectMessage("remote", false)))
+
+	msg, err := session.conn.Read()
+	assert.Nil(t, err)
+	assert.Equalf(t, expected, string(msg), "Expected to receive %s but got %s", expected, string(msg))
+	assert.True(t, session.closed)
+}
+
+func TestMarkReady(t *testing.T) {
+	node := NewMockNode()
+
+	select {
+	case <-node.Ready():
+		t.Fatal("Node must not be ready before MarkReady is called")
+	default:
+	}
+
+	node.MarkReady()
+	node.MarkReady()
+
+	select {
+	case <-node.Ready():
+	default:
+		t.Fatal("Node must be ready after MarkReady is called")
+	}
+}
+
 func TestLookupSession(t *testing.T) {
 	node := NewMockNode()
 
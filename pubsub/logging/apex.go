@@ -0,0 +1,38 @@
+package logging
+
+import (
+	"github.com/apex/log"
+)
+
+type apexLogger struct {
+	entry *log.Entry
+}
+
+var _ Logger = (*apexLogger)(nil)
+
+// NewApexLogger returns a Logger backed by the apex/log entry (the default subscribers logger)
+func NewApexLogger(entry *log.Entry) Logger {
+	return &apexLogger{entry: entry}
+}
+
+func (l *apexLogger) Debug(msg string) { l.entry.Debug(msg) }
+func (l *apexLogger) Info(msg string)  { l.entry.Info(msg) }
+func (l *apexLogger) Warn(msg string)  { l.entry.Warn(msg) }
+func (l *apexLogger) Error(msg string) { l.entry.Error(msg) }
+
+func (l *apexLogger) Debugf(msg string, args ...interface{}) { l.entry.Debugf(msg, args...) }
+func (l *apexLogger) Infof(msg string, args ...interface{})  { l.entry.Infof(msg, args...) }
+func (l *apexLogger) Warnf(msg string, args ...interface{})  { l.entry.Warnf(msg, args...) }
+func (l *apexLogger) Errorf(msg string, args ...interface{}) { l.entry.Errorf(msg, args...) }
+
+func (l *apexLogger) IsDebug() bool {
+	return l.entry.Logger.Level <= log.DebugLevel
+}
+
+func (l *apexLogger) WithField(key string, value interface{}) Logger {
+	return &apexLogger{entry: l.entry.WithField(key, value)}
+}
+
+func (l *apexLogger) WithFields(fields Fields) Logger {
+	return &apexLogger{entry: l.entry.WithFields(log.Fields(fields))}
+}
@@ -0,0 +1,31 @@
+package logging
+
+import (
+	"testing"
+
+	"github.com/apex/log"
+	"github.com/apex/log/handlers/memory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApexLogger(t *testing.T) {
+	handler := memory.New()
+	logger := NewApexLogger(log.NewEntry(&log.Logger{Handler: handler, Level: log.InfoLevel}))
+
+	assert.False(t, logger.IsDebug())
+
+	logger.Debugf("skipped %d", 1)
+	logger.WithFields(Fields{"a": 1}).WithField("b", "2").Infof("hello %s", "world")
+	logger.Error("failed")
+
+	require.Len(t, handler.Entries, 2)
+
+	assert.Equal(t, "hello world", handler.Entries[0].Message)
+	assert.Equal(t, log.InfoLevel, handler.Entries[0].Level)
+	assert.Equal(t, log.Fields{"a": 1, "b": "2"}, handler.Entries[0].Fields)
+
+	assert.Equal(t, "failed", handler.Entries[1].Message)
+	assert.Equal(t, log.ErrorLevel, handler.Entries[1].Level)
+	assert.Empty(t, handler.Entries[1].Fields)
+}
@@ -0,0 +1,28 @@
+// Package logging defines the logger interface used by the Redis subscribers, so embedders could route
+// their logs through their own logger (see adapters for apex/log and log/slog).
+// NATS and HTTP subscribers don't support custom loggers and always log via apex/log.
+package logging
+
+// Fields contains structured log fields
+type Fields map[string]interface{}
+
+// Logger is a minimal structured logger
+type Logger interface {
+	Debug(msg string)
+	Info(msg string)
+	Warn(msg string)
+	Error(msg string)
+
+	Debugf(msg string, args ...interface{})
+	Infof(msg string, args ...interface{})
+	Warnf(msg string, args ...interface{})
+	Errorf(msg string, args ...interface{})
+
+	// IsDebug returns true if debug entries are logged (to skip preparing expensive debug output otherwise)
+	IsDebug() bool
+
+	// WithField returns a logger with the field attached to all the entries
+	WithField(key string, value interface{}) Logger
+	// WithFields returns a logger with the fields attached to all the entries
+	WithFields(fields Fields) Logger
+}
@@ -0,0 +1,70 @@
+//go:build go1.21
+// +build go1.21
+
+package logging
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+)
+
+type slogLogger struct {
+	logger *slog.Logger
+}
+
+var _ Logger = (*slogLogger)(nil)
+
+// NewSlogLogger returns a Logger backed by the log/slog logger
+func NewSlogLogger(logger *slog.Logger) Logger {
+	return &slogLogger{logger: logger}
+}
+
+func (l *slogLogger) Debug(msg string) { l.logger.Debug(msg) }
+func (l *slogLogger) Info(msg string)  { l.logger.Info(msg) }
+func (l *slogLogger) Warn(msg string)  { l.logger.Warn(msg) }
+func (l *slogLogger) Error(msg string) { l.logger.Error(msg) }
+
+func (l *slogLogger) Debugf(msg string, args ...interface{}) { l.logf(slog.LevelDebug, msg, args) }
+func (l *slogLogger) Infof(msg string, args ...interface{})  { l.logf(slog.LevelInfo, msg, args) }
+func (l *slogLogger) Warnf(msg string, args ...interface{})  { l.logf(slog.LevelWarn, msg, args) }
+func (l *slogLogger) Errorf(msg string, args ...interface{}) { l.logf(slog.LevelError, msg, args) }
+
+func (l *slogLogger) IsDebug() bool {
+	return l.logger.Enabled(context.Background(), slog.LevelDebug)
+}
+
+func (l *slogLogger) WithField(key string, value interface{}) Logger {
+	return &slogLogger{logger: l.logger.With(key, value)}
+}
+
+// WithFields attaches the fields in the keys order, so the output is stable
+func (l *slogLogger) WithFields(fields Fields) Logger {
+	keys := make([]string, 0, len(fields))
+
+	for key := range fields {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	args := make([]interface{}, 0, len(fields)*2)
+
+	for _, key := range keys {
+		args = append(args, key, fields[key])
+	}
+
+	return &slogLogger{logger: l.logger.With(args...)}
+}
+
+// logf formats the message only if the level is enabled
+func (l *slogLogger) logf(level slog.Level, msg string, args []interface{}) {
+	ctx := context.Background()
+
+	if !l.logger.Enabled(ctx, level) {
+		return
+	}
+
+	l.logger.Log(ctx, level, fmt.Sprintf(msg, args...))
+}
@@ -0,0 +1,36 @@
+//go:build go1.21
+// +build go1.21
+
+package logging
+
+import (
+	"bytes"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSlogLogger(t *testing.T) {
+	var buf bytes.Buffer
+
+	handler := slog.NewTextHandler(&buf, &slog.HandlerOptions{
+		Level: slog.LevelInfo,
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if a.Key == slog.TimeKey {
+				return slog.Attr{}
+			}
+
+			return a
+		},
+	})
+
+	logger := NewSlogLogger(slog.New(handler))
+
+	assert.False(t, logger.IsDebug())
+
+	logger.Debugf("skipped %d", 1)
+	logger.WithFields(Fields{"b": 2, "a": 1}).WithField("c", "3").Warnf("hello %s", "world")
+
+	assert.Equal(t, "level=WARN msg=\"hello world\" a=1 b=2 c=3\n", buf.String())
+}
@@ -5,22 +5,68 @@ import (
 	"errors"
 	"fmt"
 	"math/rand"
+	"net"
 	"net/url"
+	"os"
+	"runtime/pprof"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/FZambia/sentinel"
 
-	"github.com/apex/log"
+	"github.com/anycable/anycable-go/metrics"
+	"github.com/anycable/anycable-go/pubsub/logging"
+	"github.com/anycable/anycable-go/utils"
 	"github.com/gomodule/redigo/redis"
 )
 
+// ErrReconnectExceeded is reported when the subscriber failed to reconnect to Redis
+// and the cold retry mode is disabled
+var ErrReconnectExceeded = errors.New("Redis reconnect attempts exceeded") //nolint:stylecheck
+
+// errRedisConnectionRotated is returned by listen when the connection reached its max lifetime
+var errRedisConnectionRotated = errors.New("Redis connection max lifetime reached") //nolint:stylecheck
+
+// errRedisSubscribeTimeout is returned by listen when Redis doesn't confirm a subscription in time
+var errRedisSubscribeTimeout = errors.New("Redis subscription confirmation timed out") //nolint:stylecheck
+
+// errRedisStaleConnection is reported by the receiving goroutine when a newer connection has been established
+var errRedisStaleConnection = errors.New("Redis connection is stale") //nolint:stylecheck
+
+// URL schemes supported by the Redis client
+var redisSupportedSchemes = []string{"redis", "rediss"}
+
 const (
+	// The max step value used to calculate reconnect delay jitter
+	maxRetryJitterStep                    = 6
 	maxReconnectAttempts                  = 5
 	defaultKeepaliveInterval              = 30
 	defaultRedisURL                       = "redis://localhost:6379/5"
 	defaultRedisChannel                   = "__anycable__"
 	defaultRedisSentinelDiscoveryInterval = 30
+	defaultRedisSentinelFallbackAttempts  = 3
+	defaultRedisTCPKeepaliveInterval      = 15
+	// The same as the Redis client uses by default
+	redisDefaultTCPKeepalive     = 5 * time.Minute
+	defaultRedisDeadLetterMaxLen = 1000
+	// Log cold retry failures only once per this number of attempts
+	redisColdRetryLogEvery = 12
+	// How long the connection must stay alive to reset reconnect attempts
+	redisStableConnectionDuration = 30 * time.Second
+	// How long to wait for unsubscribe confirmation during shutdown
+	redisUnsubscribeTimeout = 5 * time.Second
+	// Seconds
+	defaultRedisCommandTimeout = 5
+
+	metricsRedisKeepaliveFailures = "redis_keepalive_failures_total"
+	metricsRedisSubscribeFailures = "redis_subscribe_failures_total"
+	metricsRedisReceiveFailures   = "redis_receive_failures_total"
+	metricsRedisControlMsg        = "redis_control_msg_total"
+	metricsRedisDroppedMsg        = "redis_dropped_msg_total"
+	metricsRedisReceivedMsg       = "redis_received_msg_total"
+	metricsRedisHandledMsg        = "redis_handled_msg_total"
 )
 
 // RedisConfig contains Redis pubsub adapter configuration
@@ -29,50 +75,645 @@ type RedisConfig struct {
 	URL string
 	// Redis channel to subscribe to
 	Channel string
+	// Redis URL to use for health checks (primary Redis is used if empty)
+	HealthcheckURL string
+	// The max number of auxiliary connections in the pool (0 means unlimited).
+	// The pool is used for commands other than pub/sub (e.g., dead letters, replay, healthchecks);
+	// the subscription always uses a dedicated connection
+	PoolMaxActive int
+	// The max number of idle auxiliary connections in the pool
+	PoolMaxIdle int
+	// Close auxiliary connections after remaining idle for this duration (seconds; 0 means never)
+	PoolIdleTimeout int
+	// Redis list to consume broadcasts from (redis_queue adapter)
+	QueueKey string
+	// Redis channel for internal (control) messages, e.g., remote disconnects
+	InternalChannel string
+	// Subscribe to the internal channel via a separate connection, so broadcasts can't delay commands
+	// (see RedisSplitSubscriber)
+	CommandsConnection bool
+	// Comma-separated list of Redis keyspace events (e.g., "expired,del") to subscribe to via keyevent channels
+	// (__keyevent@<db>__:<event>); notifications are passed to the handler implementing KeyspaceHandler
+	KeyspaceEvents string
+	// File with additional channels to subscribe to (one per line); the file is watched for changes
+	ChannelsFile string
 	// List of Redis Sentinel addresses
 	Sentinels string
+	// The max number of sentinel addresses (0 means unlimited); the subscriber fails to start if exceeded
+	SentinelMaxAddrs int
 	// Redis Sentinel discovery interval (seconds)
 	SentinelDiscoveryInterval int
+	// Attach the resolved master address to all the log entries in sentinel mode
+	SentinelLogMaster bool
+	// The min number of sentinels that must be reachable and agree on the master address
+	// to trust the master discovery (0 means not checked)
+	SentinelMinReachable int
+	// Direct Redis URL to use when sentinels are unavailable (disabled if empty)
+	SentinelFallbackURL string
+	// The number of failed sentinel master discovery attempts in a row before falling back to the direct URL
+	SentinelFallbackAttempts int
+	// How long to wait for the master resolved via sentinels to finish the promotion (i.e., to stop reporting
+	// itself as a replica right after a failover) before abandoning the address (milliseconds; 0 means no waiting)
+	SentinelPromotionWait int
+	// Minimum required Redis server version (e.g., "6.0"); the subscriber fails if the server is older (not checked if empty)
+	MinVersion string
+	// Use Redis 7 sharded pub/sub (SSUBSCRIBE) in cluster mode.
+	// All the channels must belong to the same shard.
+	Sharded bool
+	// Subscribe via a sentinel-resolved replica instead of the master.
+	// Only works if the Redis setup propagates pub/sub messages to replicas.
+	SentinelReplica bool
+	// Max pub/sub connection lifetime (seconds); the connection is re-established when exceeded (0 means unlimited)
+	MaxConnectionLifetime int
+	// Make a single connection attempt and report its result (error or nil if the subscription is closed)
+	// without reconnecting
+	SingleShot bool
+	// Stop the subscriber and report nil (i.e., a clean exit) after N seconds regardless of the connection state
+	// (0 means unlimited); useful for short-lived tooling
+	MaxRuntime int
+	// The number of times to retry subscribing on the same connection when Redis replies with a transient
+	// busy error (e.g., LOADING after a restart) before reconnecting
+	SubscribeBusyRetries int
+	// The interval between subscribe retries on busy errors (seconds)
+	SubscribeBusyInterval int
+	// Spread reconnect attempts of a fleet of nodes over this window (seconds; 0 means disabled):
+	// every reconnect is delayed by a node-specific offset derived from ReconnectStaggerKey
+	ReconnectStaggerWindow int
+	// The node identifier to derive the reconnect offset from (hostname if empty)
+	ReconnectStaggerKey string
+	// Keep reconnecting every N seconds after the max number of reconnect attempts is reached
+	// instead of failing (0 means fail)
+	ColdRetryInterval int
+	// Connection failures during this period after start (seconds) are logged at a lower level and
+	// don't count toward the max reconnect attempts (0 means disabled)
+	WarmupPeriod int
 	// Redis keepalive ping interval (seconds)
 	KeepalivePingInterval int
+	// Timeout for connecting, writing commands, and receiving subscription confirmations (seconds).
+	// Reading messages is not limited by this timeout (see keepalive for idle connections detection).
+	CommandTimeout int
+	// How to keep the subscription connection alive: ping, subscribe-noop or none (rely on TCP keepalive)
+	KeepaliveMode string
+	// Wait for the node to become ready before subscribing to the channel
+	WaitReady bool
+	// Enable custom TCP keepalive settings for Redis connections
+	TCPKeepalive bool
+	// TCP keepalive interval (seconds)
+	TCPKeepaliveInterval int
+	// Redis connections read buffer size in bytes (the Redis client default is used if zero)
+	ReadBufferSize int
+	// Connection name prefix to set via CLIENT SETNAME (disabled if empty). The subscription connection name
+	// includes the subscribed channels, auxiliary connections are named <prefix>:aux
+	ClientName string
+	// Local address (IP or IP:port) to bind Redis and sentinel connections to (system default if empty)
+	LocalAddr string
+	// Redis list to push dropped messages to (disabled if empty)
+	DeadLetterKey string
+	// Max number of messages to keep in the dead letter list
+	DeadLetterMaxLen int
+	// Redis list with recent broadcasts maintained by the broadcaster (RPUSH + LTRIM) to replay
+	// on every (re)connect before subscribing (disabled if empty)
+	ReplayKey string
+	// The max number of recent broadcasts to replay
+	ReplaySize int
+	// Replay recent broadcasts after subscribing (via an auxiliary connection) and hold live broadcasts until the replay
+	// is completed, so nothing is missed and the delivery order is preserved
+	ReplayOrdered bool
+	// The max number of live broadcasts to hold during the ordered replay (live broadcasts are delivered right away
+	// without ordering guarantees when exceeded)
+	ReplayStagingSize int
+	// Verify Redis server TLS certificate
+	TLSVerify bool
+	// Fail to start if TLS is requested but the URL scheme is not rediss://
+	TLSStrict bool
+	// Debug: file to write the message caused panic and recent messages to (disabled if empty)
+	CrashDumpPath string
+	// Debug: max crash dump file size in bytes (the file is rotated when exceeded)
+	CrashDumpMaxSize int64
+	// Debug: file to append all received messages to as JSON lines (disabled if empty)
+	TeePath string
+	// Unix socket to write all received messages to for sidecars (disabled if empty);
+	// every message is written as the length-prefixed channel followed by the length-prefixed payload
+	TeeSocket string
+	// Use a separate connection per tap instead of the subscription connection (see RedisSubscriber.Tap)
+	TapConnection bool
+	// Broadcasts are wrapped in envelopes with stream and epoch;
+	// duplicate and out-of-order messages (per stream) are dropped
+	Envelope bool
+	// The max number of streams to track epochs for (the least recently used are evicted)
+	EnvelopeStreamsLimit int
+	// What to do with incoming messages while paused: block (stop reading from Redis) or drop
+	PauseMode string
+	// What to do with incoming messages while the handler (node) is not set: drop or buffer
+	NoHandlerPolicy string
+	// The max number of messages to buffer while the handler is not set (the rest are dropped)
+	NoHandlerBufferSize int
+	// Memory budget for internal buffers in bytes (0 means unlimited); when approached,
+	// buffered data is dropped or evicted more aggressively (according to the configured policies)
+	MemoryBudget int64
+	// What to do when the dispatch queue is full: block, drop_new or drop_oldest
+	DispatchOverflowPolicy string
+	// Apply the dispatch overflow policy right away when the node reports the pressure (see PressureReporter)
+	// at or above this value (from 0 to 1; 0 means disabled)
+	NodePressureThreshold float64
+	// The size of the lifecycle events channel buffer (0 means events are disabled, see Events)
+	EventsBufferSize int
+	// Ping the systemd watchdog (WatchdogSec=) while the subscriber is live (see Live)
+	SystemdWatchdog bool
+	// Publish a marker message with this prefix to the broadcasting channel after subscribing and consider
+	// the subscriber ready only when it comes back, i.e., end-to-end delivery works (disabled if empty).
+	// Markers are never passed to the node.
+	MarkerPayload string
+	// How long to wait for the marker to come back (seconds)
+	MarkerTimeout int
+	// Warn if the expected channels receive no messages within this window while subscribed (seconds; 0 means disabled)
+	ActivityWindow int
+	// Comma-separated list of channels expected to be active (the broadcasting channel if empty)
+	ActivityChannels string
+	// Redis list to poll for broadcasts when pub/sub is unavailable after all the reconnect attempts
+	// (disabled if empty); the subscriber keeps retrying pub/sub and stops polling when it's restored
+	PollKey string
+	// Redis URL to poll the list from (e.g., a replica; the primary Redis is used if empty)
+	PollURL string
+	// Polling interval (seconds)
+	PollInterval int
+	// Warn when the p99 latency from receiving a message to the handler return exceeds this value
+	// (milliseconds; 0 means disabled)
+	LatencyThreshold int
+	// The max number of the most recent list items to read on every poll
+	PollSize int
+	// Comma-separated list of "substring=action" rules (e.g., "max number of clients=retry-slow")
+	// to handle connection errors containing the substring (case-insensitive) with the specified action:
+	// retry-fast, retry-slow or fatal; the rules take precedence over the built-in error classification
+	ErrorRules string
+	// Static tags (labels) to attach to all the subscriber metrics
+	MetricsTags map[string]string
+	// Attach the connection tag (Redis host, port and database) to the received messages and bytes counters
+	MetricsConnectionTag bool
+	// Custom logger to use instead of the default apex/log-backed one (see logging package for adapters).
+	// Used by the Redis pub/sub and queue subscribers (NATS and HTTP subscribers always log via apex/log).
+	Logger logging.Logger
+	// Logger field key to use for the subscriber context (e.g., "context")
+	LogContextKey string
+	// Logger field value to use for the subscriber context (e.g., "pubsub")
+	LogContext string
+	// Log every N-th received message at the info level (0 means disabled)
+	LogSampleRate int
+	// Log at most one received message per interval (seconds) at the info level (0 means disabled)
+	LogSampleInterval int
+	// The max size of sampled payloads to log in bytes (longer payloads are truncated; 0 means unlimited)
+	LogSampleMaxSize int
+	// Broadcast payload field to take a correlation ID from to attach to per-message logs (disabled if empty)
+	CorrelationIDKey string
+	// Broadcast payload field to take a message ID from to skip duplicates (disabled if empty)
+	DedupKey string
+	// The max number of recent message IDs to remember
+	DedupCacheSize int
+	// For how long to remember message IDs (seconds)
+	DedupTTL int
+	// Suppress broadcasts identical to the previous one on the same channel received within this window
+	// (milliseconds; 0 means disabled)
+	CoalesceWindow int
+	// Broadcast payload field to take the broadcast time from to drop stale messages (disabled if empty)
+	MaxAgeKey string
+	// Messages broadcasted longer than this ago are dropped before dispatching (seconds; 0 means disabled)
+	MaxAge int
+	// The number of goroutines dispatching messages to the node (0 means the number of CPUs but at most 4).
+	// Broadcasts for the same stream (and other messages from the same Redis channel) are always dispatched in order
+	// by the same goroutine.
+	DispatchWorkers int
+	// The max number of times to re-attempt dispatching a message when the handler (implementing CheckedHandler)
+	// returns a RetryableError; the message is dropped if it still fails (0 means no retries)
+	DispatchRetries int
+	// The delay between dispatch attempts (milliseconds)
+	DispatchRetryDelay int
 }
 
 // NewRedisConfig builds a new config for Redis pubsub
 func NewRedisConfig() RedisConfig {
 	return RedisConfig{
 		KeepalivePingInterval:     defaultKeepaliveInterval,
+		KeepaliveMode:             redisKeepalivePing,
+		CommandTimeout:            defaultRedisCommandTimeout,
 		URL:                       defaultRedisURL,
 		Channel:                   defaultRedisChannel,
+		QueueKey:                  defaultRedisQueueKey,
 		SentinelDiscoveryInterval: defaultRedisSentinelDiscoveryInterval,
+		SentinelFallbackAttempts:  defaultRedisSentinelFallbackAttempts,
+		SentinelPromotionWait:     defaultRedisSentinelPromotionWait,
+		SentinelMaxAddrs:          defaultRedisSentinelMaxAddrs,
+		SubscribeBusyRetries:      defaultRedisSubscribeBusyRetries,
+		SubscribeBusyInterval:     defaultRedisSubscribeBusyInterval,
+		TCPKeepaliveInterval:      defaultRedisTCPKeepaliveInterval,
+		DeadLetterMaxLen:          defaultRedisDeadLetterMaxLen,
+		ReplaySize:                defaultRedisReplaySize,
+		ReplayStagingSize:         defaultRedisReplayStagingSize,
+		CrashDumpMaxSize:          defaultCrashDumpMaxSize,
+		DispatchOverflowPolicy:    dispatchOverflowBlock,
+		PauseMode:                 redisPauseBlock,
+		NoHandlerPolicy:           redisNoHandlerDrop,
+		NoHandlerBufferSize:       defaultRedisNoHandlerBufferSize,
+		EnvelopeStreamsLimit:      defaultRedisEnvelopeStreamsLimit,
+		LogContextKey:             defaultRedisLogContextKey,
+		LogContext:                defaultRedisLogContext,
+		LogSampleMaxSize:          defaultRedisLogSampleMaxSize,
+		DedupCacheSize:            defaultRedisDedupCacheSize,
+		DedupTTL:                  defaultRedisDedupTTL,
+		PoolMaxActive:             defaultRedisPoolMaxActive,
+		PoolMaxIdle:               defaultRedisPoolMaxIdle,
+		PoolIdleTimeout:           defaultRedisPoolIdleTimeout,
+		PollInterval:              defaultRedisPollInterval,
+		PollSize:                  defaultRedisPollSize,
+		MarkerTimeout:             defaultRedisMarkerTimeout,
+		DispatchRetryDelay:        defaultRedisDispatchRetryDelay,
 	}
 }
 
 // RedisSubscriber contains information about Redis pubsub connection
 type RedisSubscriber struct {
 	node                      Handler
+	nodeMu                    sync.RWMutex
+	metrics                   metrics.Instrumenter
+	receivedMsgMetric         string
+	receivedBytesMetric       string
 	url                       string
+	keyspaceEvents            string
+	keyeventChannels          []string
 	sentinels                 string
+	sentinelMaxAddrs          int
 	sentinelClient            *sentinel.Sentinel
 	sentinelDiscoveryInterval time.Duration
+	sentinelReplica           bool
+	sharded                   bool
+	sentinelFallbackURL       string
+	sentinelFallbackAttempts  int
+	promotionWait             time.Duration
+	sentinelMinReachable      int
+	sentinelLogMaster         bool
+	sentinelFailures          int
+	dnsRetries                int
+	errorRulesRaw             string
+	errorRules                []redisErrorRule
+	ruleRetries               int
+	sentinelFallback          bool
+	replicaAddr               string
+	serverVersion             string
+	minVersionRaw             string
+	minVersion                *utils.Version
 	pingInterval              time.Duration
+	keepaliveMode             string
+	commandTimeout            time.Duration
+	lastReplyAt               int64
+	generation                uint64
+	handoff                   int32
+	receivedSinceEvent        int64
+	events                    chan RedisEvent
+	eventsCloseOnce           sync.Once
+	coldRetryInterval         time.Duration
+	warmupPeriod              time.Duration
+	singleShot                bool
+	maxRuntime                time.Duration
+	maxConnectionLifetime     time.Duration
 	channel                   string
-	reconnectAttempt          int
-	uri                       *url.URL
-	log                       *log.Entry
+	internalChannel           string
+	channelsFile              string
+	channelsFileInterval      time.Duration
+	fileChannels              map[string]struct{}
+	dynamicChannels           map[string]struct{}
+	dynamicMu                 sync.Mutex
+	taps                      redisTaps
+	tapConnection             bool
+	channelsChangedCh         chan struct{}
+	probes                    redisProbes
+	probeCh                   chan string
+	subscribeRetryCh          chan struct{}
+	subscribeBusyRetries      int
+	subscribeBusyInterval     time.Duration
+	subscribeBusyAttempts     int32
+	waitReady                 bool
+	tcpKeepalive              bool
+	tcpKeepaliveInterval      time.Duration
+	readBufferSize            int
+	localAddr                 string
+	clientNamePrefix          string
+	localTCPAddr              *net.TCPAddr
+	deadLetters               *redisDeadLetters
+	deadLetterHandler         DeadLetterHandler
+	replayer                  *redisReplayer
+	subscribedHandler         func(channel string)
+	channelRewrite            ChannelRewrite
+	subscriptions             *redisSubscriptions
+	dispatchWorkers           int
+	dispatchPolicy            string
+	dispatchRetries           int
+	dispatchRetryDelay        time.Duration
+	nodePressureThreshold     float64
+	nodePressured             int32
+	pauseMode                 string
+	pause                     redisPauseState
+	noHandlerPolicy           string
+	noHandlerBufferSize       int
+	pending                   redisPendingState
+	memory                    *redisMemory
+	epochs                    *epochTracker
+	dedupKey                  string
+	dedup                     *dedupCache
+	coalescer                 *redisCoalescer
+	maxAgeKey                 string
+	maxAge                    time.Duration
+	tlsVerify                 bool
+	tlsStrict                 bool
+	healthcheckURL            string
+	correlationIDKey          string
+	sampler                   *redisSampler
+	dispatcher                *redisDispatcher
+	crashDumper               *crashDumper
+	tee                       *redisTee
+	reconnectAttempt          int32
+	gaveUp                    int32
+	systemdWatchdog           bool
+	watchdog                  *redisWatchdog
+	marker                    *redisMarker
+	markerTimeout             time.Duration
+	reconnectStagger          time.Duration
+	outage                    redisOutage
+	poolMaxActive             int
+	poolMaxIdle               int
+	poolIdleTimeout           time.Duration
+	activity                  *redisActivity
+	activityWindow            time.Duration
+	poller                    *redisPoller
+	pollURL                   string
+	latencies                 redisLatencies
+	latencyThreshold          time.Duration
+	latencySlow               int32
+	totals                    redisTotals
+	summaryOnce               sync.Once
+	stableConnectionDuration  time.Duration
+	// connect establishes the connection and blocks until it's closed (listen by default, could be replaced in tests)
+	connect      func() error
+	clock        redisClock
+	uri          *url.URL
+	urlMu        sync.RWMutex
+	pool         *redis.Pool
+	shutdownCh   chan struct{}
+	shutdownOnce sync.Once
+	wg           sync.WaitGroup
+	log          logging.Logger
+	// log with the resolved master address attached (sentinel mode only)
+	masterLog atomic.Value
+	// effective configuration (see Config)
+	config RedisConfig
+}
+
+// NewRedisSubscriber returns new RedisSubscriber struct.
+// Metrics are reported via the generic instrumenter interface (could be nil to disable metrics).
+func NewRedisSubscriber(node Handler, metrics metrics.Instrumenter, config *RedisConfig) *RedisSubscriber {
+	return newRedisSubscriber(node, metrics, config, "")
 }
 
-// NewRedisSubscriber returns new RedisSubscriber struct
-func NewRedisSubscriber(node Handler, config *RedisConfig) *RedisSubscriber {
-	return &RedisSubscriber{
+// newRedisSubscriber returns new RedisSubscriber with the role to distinguish its connection metrics
+// from the ones of other subscribers sharing the instrumenter (e.g., split connections)
+func newRedisSubscriber(node Handler, metrics metrics.Instrumenter, config *RedisConfig, role string) *RedisSubscriber {
+	metrics = instrumenterOrNoop(metrics)
+
+	var dumper *crashDumper
+
+	effective := effectiveConfig(config)
+
+	var epochs *epochTracker
+
+	if config.Envelope {
+		epochs = newEpochTracker(config.EnvelopeStreamsLimit)
+	}
+
+	memory := newRedisMemory(config.MemoryBudget)
+
+	if epochs != nil {
+		epochs.memory = memory
+	}
+
+	var dedup *dedupCache
+
+	if config.DedupKey != "" {
+		dedup = newDedupCache(config.DedupCacheSize, time.Duration(config.DedupTTL)*time.Second)
+		dedup.memory = memory
+	}
+
+	var coalescer *redisCoalescer
+
+	if config.CoalesceWindow > 0 {
+		coalescer = newRedisCoalescer(time.Duration(config.CoalesceWindow) * time.Millisecond)
+	}
+
+	subscribeCommand := "SUBSCRIBE"
+
+	if config.Sharded {
+		subscribeCommand = "SSUBSCRIBE"
+	}
+
+	if config.CrashDumpPath != "" {
+		dumper = newCrashDumper(config.CrashDumpPath, config.CrashDumpMaxSize)
+	}
+
+	registerCounter(metrics, config.MetricsTags, metricsRedisKeepaliveFailures, "The total number of failed Redis keepalive pings")
+	registerCounter(metrics, config.MetricsTags, metricsRedisSubscribeFailures, "The total number of failed Redis subscribe attempts")
+	registerCounter(metrics, config.MetricsTags, metricsRedisDispatchRetries, "The total number of re-attempted dispatches of Redis messages after retryable handler errors")
+	registerCounter(metrics, config.MetricsTags, metricsRedisDispatchFailed, "The total number of Redis messages dropped since the handler failed to process them")
+	registerCounter(metrics, config.MetricsTags, metricsRedisReceiveFailures, "The total number of Redis subscription errors while receiving messages")
+	receivedTags := receivedMetricsTags(config, role)
+
+	registerCounter(metrics, receivedTags, metricsRedisReceivedMsg, "The total number of messages received from Redis")
+	registerCounter(metrics, receivedTags, metricsRedisReceivedBytes, "The total size of messages received from Redis in bytes")
+	registerCounter(metrics, config.MetricsTags, metricsRedisHandledMsg, "The total number of messages received from Redis and successfully handled by the node")
+	registerCounter(metrics, config.MetricsTags, metricsRedisDroppedMsg, "The total number of messages received from Redis and dropped without delivering")
+	registerCounter(metrics, config.MetricsTags, metricsRedisDispatchBlocked, "The total number of times Redis messages dispatching was blocked due to a full queue")
+	registerCounter(metrics, config.MetricsTags, metricsRedisDispatchDroppedNew, "The total number of incoming Redis messages dropped due to a full dispatch queue")
+	registerCounter(metrics, config.MetricsTags, metricsRedisDispatchDroppedOldest, "The total number of enqueued Redis messages evicted due to a full dispatch queue")
+	registerCounter(metrics, config.MetricsTags, metricsRedisNodePressure, "The total number of Redis messages dispatched while the node was under pressure")
+	registerGauge(metrics, config.MetricsTags, metricsRedisMemoryUsed, "The estimated memory used by Redis subscriber buffers in bytes")
+	registerGauge(metrics, config.MetricsTags, metricsRedisPaused, "Whether Redis messages delivery is paused (1) or not (0)")
+	registerGauge(metrics, config.MetricsTags, metricsRedisHandleLatencyP50, "The median time from receiving a Redis message to the handler return (ms)")
+	registerGauge(metrics, config.MetricsTags, metricsRedisHandleLatencyP99, "The p99 time from receiving a Redis message to the handler return (ms)")
+	registerGauge(metrics, config.MetricsTags, metricsRedisPoolActive, "The number of connections in the Redis auxiliary pool")
+	registerGauge(metrics, config.MetricsTags, metricsRedisPoolIdle, "The number of idle connections in the Redis auxiliary pool")
+	registerCounter(metrics, config.MetricsTags, metricsRedisPoolWaits, "The total number of times Redis auxiliary pool borrowers had to wait")
+
+	// Optional features metrics are only registered if the features are enabled
+	if config.InternalChannel != "" {
+		registerCounter(metrics, config.MetricsTags, metricsRedisControlMsg, "The total number of control messages received via Redis internal channel")
+	}
+
+	if config.TeeSocket != "" {
+		registerCounter(metrics, config.MetricsTags, metricsRedisTeeSocketDropped, "The total number of received Redis messages not written to the tee socket (buffer is full or socket is unavailable)")
+	}
+
+	if config.MarkerPayload != "" {
+		registerCounter(metrics, config.MetricsTags, metricsRedisMarkerFailures, "The total number of Redis delivery markers failed to publish or come back in time")
+	}
+
+	if config.ActivityWindow > 0 {
+		registerCounter(metrics, config.MetricsTags, metricsRedisInactiveChannels, "The total number of windows expected Redis channels received no messages in")
+	}
+
+	if config.DedupKey != "" {
+		registerCounter(metrics, config.MetricsTags, metricsRedisDedupHits, "The total number of duplicate Redis messages skipped")
+	}
+
+	if config.CoalesceWindow > 0 {
+		registerCounter(metrics, config.MetricsTags, metricsRedisCoalescedMsg, "The total number of repeated Redis messages suppressed")
+	}
+
+	if config.MaxAgeKey != "" && config.MaxAge > 0 {
+		registerCounter(metrics, config.MetricsTags, metricsRedisExpiredMsg, "The total number of Redis messages dropped due to exceeding the max age")
+	}
+
+	if config.ReplayKey != "" && config.ReplaySize > 0 {
+		registerCounter(metrics, config.MetricsTags, metricsRedisReplayedMsg, "The total number of recent broadcasts replayed from the Redis list on connect")
+	}
+
+	if config.PollKey != "" {
+		registerGauge(metrics, config.MetricsTags, metricsRedisPolling, "Whether broadcasts are polled from Redis list (1) or received via pub/sub (0)")
+		registerCounter(metrics, config.MetricsTags, metricsRedisPolledMsg, "The total number of broadcasts polled from Redis list while pub/sub is unavailable")
+	}
+
+	subscriber := &RedisSubscriber{
 		node:                      node,
+		metrics:                   metrics,
+		receivedMsgMetric:         metricKey(metrics, receivedTags, metricsRedisReceivedMsg),
+		receivedBytesMetric:       metricKey(metrics, receivedTags, metricsRedisReceivedBytes),
 		url:                       config.URL,
+		keyspaceEvents:            config.KeyspaceEvents,
 		sentinels:                 config.Sentinels,
+		sentinelMaxAddrs:          config.SentinelMaxAddrs,
 		sentinelDiscoveryInterval: time.Duration(config.SentinelDiscoveryInterval),
+		sentinelReplica:           config.SentinelReplica,
+		sharded:                   config.Sharded,
+		minVersionRaw:             config.MinVersion,
+		sentinelFallbackURL:       config.SentinelFallbackURL,
+		sentinelFallbackAttempts:  config.SentinelFallbackAttempts,
+		promotionWait:             time.Duration(config.SentinelPromotionWait) * time.Millisecond,
+		sentinelMinReachable:      config.SentinelMinReachable,
+		sentinelLogMaster:         config.SentinelLogMaster,
 		channel:                   config.Channel,
+		internalChannel:           config.InternalChannel,
+		channelsFile:              config.ChannelsFile,
+		channelsFileInterval:      redisChannelsFilePollInterval,
+		dynamicChannels:           make(map[string]struct{}),
+		channelsChangedCh:         make(chan struct{}, 1),
+		probeCh:                   make(chan string),
+		subscribeRetryCh:          make(chan struct{}, 1),
+		subscribeBusyRetries:      config.SubscribeBusyRetries,
+		subscribeBusyInterval:     time.Duration(config.SubscribeBusyInterval) * time.Second,
 		pingInterval:              time.Duration(config.KeepalivePingInterval),
+		keepaliveMode:             effective.KeepaliveMode,
+		commandTimeout:            time.Duration(config.CommandTimeout) * time.Second,
+		coldRetryInterval:         time.Duration(config.ColdRetryInterval) * time.Second,
+		systemdWatchdog:           config.SystemdWatchdog,
+		poolMaxActive:             config.PoolMaxActive,
+		poolMaxIdle:               config.PoolMaxIdle,
+		poolIdleTimeout:           time.Duration(config.PoolIdleTimeout) * time.Second,
+		activityWindow:            time.Duration(config.ActivityWindow) * time.Second,
+		pollURL:                   config.PollURL,
+		latencyThreshold:          time.Duration(config.LatencyThreshold) * time.Millisecond,
+		marker:                    newRedisMarker(config.MarkerPayload),
+		markerTimeout:             time.Duration(config.MarkerTimeout) * time.Second,
+		warmupPeriod:              time.Duration(config.WarmupPeriod) * time.Second,
+		singleShot:                config.SingleShot,
+		maxRuntime:                time.Duration(config.MaxRuntime) * time.Second,
+		maxConnectionLifetime:     time.Duration(config.MaxConnectionLifetime) * time.Second,
+		waitReady:                 config.WaitReady,
+		tcpKeepalive:              effective.TCPKeepalive,
+		tcpKeepaliveInterval:      time.Duration(config.TCPKeepaliveInterval) * time.Second,
+		readBufferSize:            config.ReadBufferSize,
+		localAddr:                 config.LocalAddr,
+		clientNamePrefix:          config.ClientName,
+		dispatchWorkers:           config.DispatchWorkers,
+		dispatchPolicy:            effective.DispatchOverflowPolicy,
+		dispatchRetries:           config.DispatchRetries,
+		dispatchRetryDelay:        time.Duration(config.DispatchRetryDelay) * time.Millisecond,
+		nodePressureThreshold:     config.NodePressureThreshold,
+		pauseMode:                 effective.PauseMode,
+		noHandlerPolicy:           effective.NoHandlerPolicy,
+		noHandlerBufferSize:       config.NoHandlerBufferSize,
+		errorRulesRaw:             config.ErrorRules,
+		memory:                    memory,
+		epochs:                    epochs,
+		dedupKey:                  config.DedupKey,
+		dedup:                     dedup,
+		coalescer:                 coalescer,
+		maxAgeKey:                 config.MaxAgeKey,
+		maxAge:                    time.Duration(config.MaxAge) * time.Second,
+		tlsVerify:                 config.TLSVerify,
+		tlsStrict:                 config.TLSStrict,
+		healthcheckURL:            config.HealthcheckURL,
+		correlationIDKey:          config.CorrelationIDKey,
+		sampler:                   newRedisSampler(config.LogSampleRate, time.Duration(config.LogSampleInterval)*time.Second, config.LogSampleMaxSize),
+		crashDumper:               dumper,
+		tapConnection:             config.TapConnection,
+		subscriptions:             newRedisSubscriptions(subscribeCommand),
 		reconnectAttempt:          0,
-		log:                       log.WithFields(log.Fields{"context": "pubsub"}),
+		reconnectStagger:          reconnectStagger(config.ReconnectStaggerKey, time.Duration(config.ReconnectStaggerWindow)*time.Second),
+		stableConnectionDuration:  redisStableConnectionDuration,
+		clock:                     defaultRedisClock(),
+		shutdownCh:                make(chan struct{}),
+		log:                       newRedisLogger(&effective),
+		config:                    effective,
 	}
+
+	if config.EventsBufferSize > 0 {
+		subscriber.events = make(chan RedisEvent, config.EventsBufferSize)
+	}
+
+	if config.ActivityWindow > 0 {
+		subscriber.activity = newRedisActivity(config.ActivityChannels, subscriber.channel)
+	}
+
+	subscriber.tee = newRedisTee(
+		redisTeeConfig{Path: config.TeePath, Socket: config.TeeSocket},
+		metrics, subscriber.logger,
+	)
+
+	subscriber.deadLetters = newRedisDeadLetters(
+		redisDeadLetterConfig{Key: config.DeadLetterKey, MaxLen: config.DeadLetterMaxLen},
+		func() redis.Conn { return subscriber.pool.Get() }, subscriber.logger,
+	)
+	subscriber.deadLetters.SetMemory(memory)
+
+	subscriber.replayer = newRedisReplayer(
+		redisReplayConfig{
+			Key:            config.ReplayKey,
+			Size:           config.ReplaySize,
+			Ordered:        config.ReplayOrdered,
+			StagingSize:    config.ReplayStagingSize,
+			Channel:        config.Channel,
+			CommandTimeout: subscriber.commandTimeout,
+		},
+		subscriber.accept, metrics, subscriber.logger,
+	)
+	subscriber.replayer.SetMemory(memory)
+
+	subscriber.poller = newRedisPoller(
+		redisPollingConfig{
+			Key:            config.PollKey,
+			Interval:       time.Duration(config.PollInterval) * time.Second,
+			Size:           config.PollSize,
+			CommandTimeout: subscriber.commandTimeout,
+		},
+		subscriber.dialPoll,
+		func(data []byte) { subscriber.accept(subscriber.channel, data) },
+		metrics, subscriber.logger,
+	)
+
+	subscriber.connect = subscriber.listen
+
+	return subscriber
+}
+
+// OnSubscribed sets a callback to be called every time a subscription to a channel is confirmed
+// (for each channel individually). The callback is called in a separate goroutine.
+func (s *RedisSubscriber) OnSubscribed(callback func(channel string)) {
+	s.subscribedHandler = callback
 }
 
 // Start connects to Redis and subscribes to the pubsub channel
@@ -87,12 +728,93 @@ func (s *RedisSubscriber) Start(done chan (error)) error {
 		return err
 	}
 
+	if err = checkRedisScheme(redisURL); err != nil {
+		return err
+	}
+
+	if err = s.checkTLS(redisURL); err != nil {
+		return err
+	}
+
+	if err = validateDispatchOverflowPolicy(s.dispatchPolicy); err != nil {
+		return err
+	}
+
+	if err = validateRedisKeepaliveMode(s.keepaliveMode); err != nil {
+		return err
+	}
+
+	if err = validateRedisPauseMode(s.pauseMode); err != nil {
+		return err
+	}
+
+	if err = validateRedisNoHandlerPolicy(s.noHandlerPolicy); err != nil {
+		return err
+	}
+
+	if s.minVersionRaw != "" {
+		minVersion, err := utils.ParseVersion(s.minVersionRaw)
+
+		if err != nil {
+			return fmt.Errorf("invalid minimum Redis version: %w", err)
+		}
+
+		s.minVersion = &minVersion
+	}
+
+	if s.keyspaceEvents != "" {
+		if s.sharded {
+			return fmt.Errorf("Redis keyspace events are not supported in sharded mode") //nolint:stylecheck
+		}
+
+		if s.keyeventChannels, err = parseKeyeventChannels(s.keyspaceEvents, redisURL); err != nil {
+			return err
+		}
+
+		if _, ok := s.currentHandler().(KeyspaceHandler); !ok {
+			s.logger().Warn("Handler doesn't support Redis keyspace events, notifications will be dropped")
+		}
+	}
+
+	if s.errorRulesRaw != "" {
+		if s.errorRules, err = parseRedisErrorRules(s.errorRulesRaw); err != nil {
+			return err
+		}
+	}
+
+	var sentinels []string
+
+	if s.sentinels != "" {
+		if sentinels, err = parseSentinels(s.sentinels, s.sentinelMaxAddrs); err != nil {
+			return err
+		}
+	}
+
+	if s.localAddr != "" {
+		if s.localTCPAddr, err = resolveLocalAddr(s.localAddr); err != nil {
+			return err
+		}
+	}
+
+	var channelsFileData []byte
+
+	if s.channelsFile != "" {
+		if channelsFileData, err = os.ReadFile(s.channelsFile); err != nil {
+			return fmt.Errorf("failed to read channels file: %w", err)
+		}
+
+		s.loadChannelsFile(channelsFileData)
+	}
+
+	if err = s.tee.Open(); err != nil {
+		return err
+	}
+
 	if s.sentinels != "" {
 		masterName := redisURL.Hostname()
 
-		s.log.Debug("Redis sentinel enabled")
-		s.log.Debugf("Redis sentinel parameters:  sentinels: %s,  masterName: %s", s.sentinels, masterName)
-		sentinels := strings.Split(s.sentinels, ",")
+		s.logger().Debug("Redis sentinel enabled")
+		s.logger().Debugf("Redis sentinel parameters:  sentinels: %s,  masterName: %s", s.sentinels, masterName)
 		s.sentinelClient = &sentinel.Sentinel{
 			Addrs:      sentinels,
 			MasterName: masterName,
@@ -103,9 +825,10 @@ func (s *RedisSubscriber) Start(done chan (error)) error {
 				dialOptions := []redis.DialOption{
 					redis.DialConnectTimeout(timeout),
 					redis.DialReadTimeout(timeout),
-					redis.DialReadTimeout(timeout),
+					redis.DialWriteTimeout(timeout),
 					redis.DialTLSSkipVerify(true),
 				}
+				dialOptions = append(dialOptions, s.netDialOptions(timeout)...)
 
 				sentinelURI, err := url.Parse(fmt.Sprintf("redis://%s", addr))
 
@@ -123,10 +846,10 @@ func (s *RedisSubscriber) Start(done chan (error)) error {
 					dialOptions...,
 				)
 				if err != nil {
-					s.log.Debugf("Failed to connect to sentinel %s", addr)
+					s.logger().Debugf("Failed to connect to sentinel %s", addr)
 					return nil, err
 				}
-				s.log.Debugf("Successfully connected to sentinel %s", addr)
+				s.logger().Debugf("Successfully connected to sentinel %s", addr)
 				return c, nil
 			},
 		}
@@ -134,11 +857,85 @@ func (s *RedisSubscriber) Start(done chan (error)) error {
 		go s.discoverSentinels()
 	}
 
+	s.pool = s.newPool()
+
+	s.wg.Add(1)
+	go s.collectStats()
+
+	handler := s.handleMessageAt
+
+	if s.crashDumper != nil {
+		s.logger().Debugf("Redis messages crash dumps are enabled: %s", s.crashDumper.path)
+		handler = s.handleMessageWithCrashDump
+	}
+
+	s.dispatcher = newRedisDispatcher(s.dispatchWorkers, handler)
+	s.dispatcher.SetOverflowPolicy(s.dispatchPolicy, s.handleDispatchOverflow)
+	s.dispatcher.SetMemory(s.memory)
+	s.dispatcher.SetPressure(s.nodeUnderPressure)
+	s.dispatcher.Start()
+
+	s.logger().Debugf("Redis messages dispatch workers: %d (overflow policy: %s)", s.dispatcher.Size(), s.dispatchPolicy)
+
+	if s.deadLetters.Enabled() {
+		s.deadLetters.Start()
+	}
+
+	if s.channelsFile != "" {
+		s.wg.Add(1)
+		go s.watchChannelsFile(channelsFileData)
+	}
+
+	if s.tee.Enabled() {
+		s.tee.Start()
+	}
+
+	if s.activityWindow > 0 {
+		s.wg.Add(1)
+		go s.watchActivity()
+	}
+
+	if s.systemdWatchdog {
+		if interval, err := systemdWatchdogInterval(); err != nil {
+			s.logger().Warnf("Redis subscriber systemd watchdog is disabled: %v", err)
+		} else {
+			s.watchdog = newRedisWatchdog(redisWatchdogConfig{Interval: interval}, s.Live, s.logger)
+			s.watchdog.Start()
+		}
+	}
+
+	s.totals.startedAt = time.Now()
+
+	s.wg.Add(1)
 	go s.keepalive(done)
 
+	if s.maxRuntime > 0 {
+		go s.expire(done)
+	}
+
 	return nil
 }
 
+// expire stops the subscriber when the max runtime is reached and reports a clean exit.
+// It's not tracked by the wait group, so Shutdown doesn't wait for the result to be read.
+func (s *RedisSubscriber) expire(done chan (error)) {
+	timer := time.NewTimer(s.maxRuntime)
+	defer timer.Stop()
+
+	select {
+	case <-s.shutdownCh:
+		return
+	case <-timer.C:
+	}
+
+	s.logger().Infof("Redis subscriber max runtime (%s) reached, stopping", s.maxRuntime)
+
+	// Stopping unblocks the reconnect backoff and makes the receive loop unsubscribe
+	s.shutdownOnce.Do(func() { close(s.shutdownCh) })
+
+	done <- nil
+}
+
 func (s *RedisSubscriber) discoverSentinels() {
 	defer s.sentinelClient.Close()
 
@@ -149,7 +946,7 @@ func (s *RedisSubscriber) discoverSentinels() {
 	go func() {
 		err := s.sentinelClient.Discover()
 		if err != nil {
-			s.log.Warn("Failed to discover sentinels")
+			s.logger().Warn("Failed to discover sentinels")
 		}
 		for {
 			select {
@@ -159,7 +956,7 @@ func (s *RedisSubscriber) discoverSentinels() {
 			case <-time.After(s.sentinelDiscoveryInterval * time.Second):
 				err := s.sentinelClient.Discover()
 				if err != nil {
-					s.log.Warn("Failed to discover sentinels")
+					s.logger().Warn("Failed to discover sentinels")
 				}
 			}
 		}
@@ -167,97 +964,594 @@ func (s *RedisSubscriber) discoverSentinels() {
 }
 
 func (s *RedisSubscriber) keepalive(done chan (error)) {
+	defer s.wg.Done()
+
+	if !s.waitForReady() {
+		return
+	}
+
+	var warmupAttempts int32
+
 	for {
+		var err error
+
 		if s.sentinelClient != nil {
-			masterAddress, err := s.sentinelClient.MasterAddr()
+			if err = s.resolveSentinelMaster(); err != nil && s.sentinelFallbackURL == "" {
+				s.giveUp(done, err)
+				return
+			}
+		}
+
+		cold := s.coldRetryInterval > 0 && s.ReconnectAttempts() >= maxReconnectAttempts
+
+		if err == nil {
+			atomic.AddInt64(&s.totals.connects, 1)
+			err = s.connect()
+		}
+
+		// Rotation is not a failure: reconnect right away
+		if err == errRedisConnectionRotated && !s.stopped() {
+			continue
+		}
+
+		// Configured error rules take precedence over the built-in classification
+		rule, matched := s.errorRuleFor(err)
+
+		if matched && rule.action == redisErrorFatal && !s.singleShot && !s.stopped() {
+			s.logger().Errorf("Redis connection failed with a fatal error (matched error rule %q): %v", rule.pattern, err)
+			s.giveUp(done, err)
+			return
+		}
+
+		// Reconnecting doesn't help if the server is too old
+		if errors.Is(err, ErrRedisVersionTooOld) && !matched && !s.singleShot {
+			s.logger().Errorf("%v", err)
+			s.giveUp(done, err)
+			return
+		}
 
-			if err != nil {
-				s.log.Warn("Failed to get master address from sentinel.")
+		// In the single-shot mode, the caller is responsible for reconnecting
+		if s.singleShot {
+			if !s.stopped() {
 				done <- err
-				return
 			}
-			s.log.Debugf("Got master address from sentinel: %s", masterAddress)
 
-			s.uri.Host = masterAddress
-			s.url = s.uri.String()
+			return
+		}
+
+		first := err != nil && s.outage.fail(time.Now())
+
+		// Only the first failure of an outage is logged prominently
+		if err != nil {
+			s.logConnectError(err, cold || s.warmingUp() || !first)
 		}
 
-		if err := s.listen(); err != nil {
-			s.log.Warnf("Redis connection failed: %v", err)
+		if s.stopped() {
+			return
 		}
 
-		s.reconnectAttempt++
+		if matched {
+			if s.retryByRule(rule) {
+				continue
+			}
 
-		if s.reconnectAttempt >= maxReconnectAttempts {
-			done <- errors.New("Redis reconnect attempts exceeded") //nolint:stylecheck
 			return
 		}
 
-		delay := nextRetry(s.reconnectAttempt)
+		if s.retryDNSFailure(err) {
+			continue
+		}
+
+		attempt := s.nextAttempt(&warmupAttempts)
+
+		var delay time.Duration
+
+		if attempt >= maxReconnectAttempts {
+			retryInterval := s.coldRetryInterval
+
+			// Keep retrying pub/sub while polling
+			if s.poller.Enabled() {
+				s.poller.Start()
+
+				if retryInterval == 0 {
+					retryInterval = redisPollRetryInterval
+				}
+			}
+
+			if retryInterval == 0 {
+				s.giveUp(done, ErrReconnectExceeded)
+				return
+			}
+
+			delay = retryInterval
+
+			// Log at a reduced cadence in the cold retry mode
+			if attempt == maxReconnectAttempts || (attempt-maxReconnectAttempts)%redisColdRetryLogEvery == 0 {
+				s.logger().Warnf("Redis is still unavailable after %d reconnect attempts, retrying every %s", attempt, delay)
+			}
+		} else {
+			delay = nextRetryWithRand(int(attempt), s.clock.intn)
+		}
+
+		delay += s.reconnectStagger
 
-		s.log.Infof("Next Redis reconnect attempt in %s", delay)
-		time.Sleep(delay)
+		s.emitEvent(RedisEvent{Kind: RedisEventReconnectScheduled, Delay: delay})
 
-		s.log.Infof("Reconnecting to Redis...")
+		logf := s.reconnectLogf(first)
+
+		if delay > 0 {
+			if attempt < maxReconnectAttempts {
+				logf("Next Redis reconnect attempt in %s", delay)
+			}
+
+			select {
+			case <-s.shutdownCh:
+				return
+			case <-s.clock.after(delay):
+			}
+		}
+
+		if attempt < maxReconnectAttempts {
+			logf("Reconnecting to Redis...")
+		}
 	}
 }
 
-// Shutdown is no-op for Redis
-func (s *RedisSubscriber) Shutdown() error {
+// resolveSentinelMaster updates the Redis URL with the master address provided by sentinels.
+// If sentinels are unavailable for the configured number of attempts in a row and the fallback URL is provided,
+// the fallback URL is used until sentinels are back.
+func (s *RedisSubscriber) resolveSentinelMaster() error {
+	startedAt := time.Now()
+	masterAddress, err := s.sentinelClient.MasterAddr()
+
+	s.logger().WithField("duration", time.Since(startedAt)).Debug("Sentinel master address resolution finished")
+
+	if err != nil {
+		s.sentinelFailures++
+
+		if s.sentinelFallbackURL == "" || s.sentinelFailures < s.sentinelFallbackAttempts {
+			s.logger().Warn("Failed to get master address from sentinel.")
+			return err
+		}
+
+		if !s.sentinelFallback {
+			s.logger().Warnf("Sentinels are unavailable after %d attempts, falling back to direct Redis URL", s.sentinelFailures)
+			s.sentinelFallback = true
+		}
+
+		s.setReplicaAddr("")
+		s.setURL(s.sentinelFallbackURL)
+
+		if fallbackURI, err := url.Parse(s.sentinelFallbackURL); err == nil {
+			s.setLogMaster(fallbackURI.Host)
+		}
+
+		return nil
+	}
+
+	if s.sentinelFallback {
+		s.logger().Info("Sentinels are available again, leaving direct Redis URL fallback mode")
+		s.sentinelFallback = false
+	}
+
+	s.sentinelFailures = 0
+
+	s.logger().Debugf("Got master address from sentinel: %s", masterAddress)
+
+	if err = s.checkSentinelQuorum(masterAddress); err != nil {
+		s.logger().Warnf("Not trusting master address from sentinel: %v", err)
+		return err
+	}
+
+	s.urlMu.Lock()
+	s.uri.Host = masterAddress
+	s.url = s.uri.String()
+	s.urlMu.Unlock()
+
+	s.setLogMaster(masterAddress)
+
+	if s.sentinelReplica {
+		s.resolveSentinelReplica()
+	}
+
 	return nil
 }
 
-func (s *RedisSubscriber) listen() error {
+// waitForReady blocks until the node is ready to deliver broadcasts (if WaitReady is enabled).
+// Thus, we guarantee that no messages are passed to the node before it's ready.
+// Returns false if the subscriber has been shut down while waiting.
+func (s *RedisSubscriber) waitForReady() bool {
+	if !s.waitReady {
+		return true
+	}
+
+	notifier, ok := s.currentHandler().(ReadyNotifier)
+
+	if !ok {
+		s.logger().Warn("Node doesn't support readiness notifications, subscribing right away")
+		return true
+	}
+
+	s.logger().Debug("Waiting for the node to become ready before subscribing")
+
+	select {
+	case <-notifier.Ready():
+		return true
+	case <-s.shutdownCh:
+		return false
+	}
+}
+
+// dialOptions returns dial options for Redis connections
+func (s *RedisSubscriber) dialOptions() []redis.DialOption {
 	dialOptions := []redis.DialOption{
-		redis.DialTLSSkipVerify(true),
+		redis.DialTLSSkipVerify(!s.tlsVerify),
+	}
+
+	// Read timeout is not set: the pub/sub connection could be idle for a long time
+	if s.commandTimeout > 0 {
+		dialOptions = append(dialOptions, redis.DialConnectTimeout(s.commandTimeout), redis.DialWriteTimeout(s.commandTimeout))
+	}
+
+	return append(dialOptions, s.netDialOptions(s.commandTimeout)...)
+}
+
+// checkTLS verifies that the connection is encrypted when TLS settings are provided
+func (s *RedisSubscriber) checkTLS(uri *url.URL) error {
+	if uri.Scheme == "rediss" || (!s.tlsVerify && !s.tlsStrict) {
+		return nil
+	}
+
+	if s.tlsStrict {
+		return fmt.Errorf("Redis TLS is required but the URL scheme is %q (use rediss://)", uri.Scheme)
 	}
-	c, err := redis.DialURL(s.url, dialOptions...)
+
+	s.logger().Warnf("Redis TLS verification is enabled but the connection is not encrypted (URL scheme: %q)", uri.Scheme)
+
+	return nil
+}
+
+// netDialOptions returns dial options to configure the underlying TCP connections
+// (both to Redis and sentinels)
+func (s *RedisSubscriber) netDialOptions(connectTimeout time.Duration) []redis.DialOption {
+	if !s.tcpKeepalive && s.readBufferSize <= 0 && s.localTCPAddr == nil {
+		return nil
+	}
+
+	dialer := &net.Dialer{
+		Timeout:   connectTimeout,
+		KeepAlive: redisDefaultTCPKeepalive,
+	}
+
+	if s.tcpKeepalive {
+		dialer.KeepAlive = s.tcpKeepaliveInterval
+	}
+
+	if s.localTCPAddr != nil {
+		dialer.LocalAddr = s.localTCPAddr
+	}
+
+	return []redis.DialOption{redis.DialNetDial(func(network, addr string) (net.Conn, error) {
+		conn, err := dialer.Dial(network, addr)
+
+		if err != nil {
+			if s.localTCPAddr != nil {
+				return nil, fmt.Errorf("failed to dial %s from local address %s: %w", addr, s.localTCPAddr, err)
+			}
+
+			return nil, err
+		}
+
+		if s.readBufferSize <= 0 {
+			return conn, nil
+		}
+
+		return newBufferedConn(conn, s.readBufferSize), nil
+	})}
+}
+
+// resolveLocalAddr parses the local address to bind connections to (IP or IP:port)
+func resolveLocalAddr(addr string) (*net.TCPAddr, error) {
+	if ip := net.ParseIP(addr); ip != nil {
+		return &net.TCPAddr{IP: ip}, nil
+	}
+
+	tcpAddr, err := net.ResolveTCPAddr("tcp", addr)
+
+	if err != nil {
+		return nil, fmt.Errorf("invalid Redis local address %q: %w", addr, err)
+	}
+
+	return tcpAddr, nil
+}
+
+// Shutdown unsubscribes from Redis and waits for the receiving goroutine to finish.
+// Messages being processed are handled before Shutdown returns, and no more messages
+// are passed to the node after that. Thus, the node must be shut down after the subscriber.
+func (s *RedisSubscriber) Shutdown() error {
+	s.shutdownOnce.Do(func() {
+		s.logger().Debug("Shutting down Redis subscriber")
+		close(s.shutdownCh)
+	})
+
+	s.wg.Wait()
+
+	s.stopComponents()
+
+	// Dispatch remaining messages
+	if s.dispatcher != nil {
+		s.dispatcher.Stop()
+	}
+
+	s.emitReceivedEvent()
+	s.closeEvents()
+	s.logSummary()
+
+	if s.pool != nil {
+		s.pool.Close()
+	}
+
+	return nil
+}
+
+// stopComponents stops the background components (after the connections are closed, so none of them is restarted)
+func (s *RedisSubscriber) stopComponents() {
+	s.poller.Stop()
+	s.tee.Stop()
+	s.deadLetters.Stop()
+
+	if s.watchdog != nil {
+		s.watchdog.Stop()
+	}
+}
+
+// ReconnectAttempts returns the number of failed reconnect attempts since the last successful connection
+func (s *RedisSubscriber) ReconnectAttempts() int {
+	return int(atomic.LoadInt32(&s.reconnectAttempt))
+}
+
+// ResetReconnectAttempts resets the failed reconnect attempts counter
+// (e.g., to prevent the subscriber from giving up after Redis issues have been fixed)
+func (s *RedisSubscriber) ResetReconnectAttempts() {
+	atomic.StoreInt32(&s.reconnectAttempt, 0)
+}
+
+func (s *RedisSubscriber) currentURL() string {
+	s.urlMu.RLock()
+	defer s.urlMu.RUnlock()
+
+	return s.url
+}
+
+// SetHandler replaces the handler messages are passed to (e.g., after the node is restarted).
+// Could be called while the subscriber is running; messages being processed are delivered to the previous handler.
+// Messages buffered while there was no handler are delivered to the new one.
+func (s *RedisSubscriber) SetHandler(node Handler) {
+	// The handler is published only after the buffered messages are delivered,
+	// so messages received in the meantime wait for the pending lock (see handlerOrHold) and are delivered after them
+	s.pending.mu.Lock()
+	defer s.pending.mu.Unlock()
+
+	if !isNilHandler(node) {
+		s.flushPendingLocked(node)
+	}
+
+	s.nodeMu.Lock()
+	s.node = node
+	s.nodeMu.Unlock()
+}
+
+func (s *RedisSubscriber) currentHandler() Handler {
+	s.nodeMu.RLock()
+	defer s.nodeMu.RUnlock()
+
+	return s.node
+}
+
+func (s *RedisSubscriber) setURL(redisURL string) {
+	s.urlMu.Lock()
+	defer s.urlMu.Unlock()
+
+	s.url = redisURL
+}
+
+func (s *RedisSubscriber) stopped() bool {
+	select {
+	case <-s.shutdownCh:
+		return true
+	default:
+		return false
+	}
+}
+
+func (s *RedisSubscriber) listen() (err error) {
+	s.emitEvent(RedisEvent{Kind: RedisEventConnecting})
+
+	subscribeURL, role := s.subscriptionTarget()
+
+	if s.sharded {
+		shardedURL, err := s.shardURL(subscribeURL)
+
+		if err != nil {
+			return err
+		}
+
+		subscribeURL = shardedURL
+	}
+
+	dialStartedAt := time.Now()
+
+	channels := s.channels()
+	dialOptions := append(s.dialOptions(), s.clientNameOptions(strings.Join(channels, ","))...)
+
+	c, err := redis.DialURL(subscribeURL, dialOptions...)
 
 	if err != nil {
+		s.logger().WithField("duration", time.Since(dialStartedAt)).Debugf("Failed to connect to Redis: %v", err)
 		return err
 	}
 
 	defer c.Close()
 
+	s.logger().WithField("duration", time.Since(dialStartedAt)).Debug("Connected to Redis")
+
+	s.emitEvent(RedisEvent{Kind: RedisEventConnected})
+	defer func() { s.emitEvent(RedisEvent{Kind: RedisEventDisconnected, Err: err}) }()
+
 	if s.sentinels != "" {
-		if !sentinel.TestRole(c, "master") {
-			return errors.New("Failed master role check")
+		if !s.testRole(c, role) {
+			return fmt.Errorf("Failed %s role check", role) //nolint:stylecheck
 		}
 	}
 
+	if err = s.checkCapabilities(c, subscribeURL); err != nil {
+		return err
+	}
+
+	s.checkKeyspaceEvents(c)
+
+	if s.replayer.Ordered() {
+		// Live broadcasts are staged until the replay triggered by the subscription confirmation is completed
+		defer s.replayer.Flush(s.replayer.StartStaging())
+	} else {
+		// Replay must happen before subscribing, so replayed messages are dispatched before live ones
+		s.replayer.Replay(c)
+	}
+
 	psc := redis.PubSubConn{Conn: c}
-	if err = psc.Subscribe(s.channel); err != nil {
-		s.log.Errorf("Failed to subscribe to Redis channel: %v", err)
+	defer s.subscriptions.reset()
+
+	if err = s.subscriptions.subscribe(psc, channels); err != nil {
+		s.metrics.CounterIncrement(metricsRedisSubscribeFailures)
+		s.logger().Errorf("Failed to subscribe to Redis channel: %v", err)
 		return err
 	}
 
-	s.reconnectAttempt = 0
+	return s.serve(psc)
+}
+
+// serve receives messages and keeps the subscription alive until shutdown (returns nil) or failure (returns error).
+// On shutdown, channels are unsubscribed gracefully; on failure, the connection is closed right away,
+// since it could be dead and never confirm unsubscribing.
+func (s *RedisSubscriber) serve(psc redis.PubSubConn) error {
+	var err error
+
+	// Reconnect attempts are only reset when the connection has been stable for a while;
+	// otherwise, a flapping connection (subscribes and drops right away) would never hit the max attempts limit
+	stableTimer := time.NewTimer(s.stableConnectionDuration)
+	defer stableTimer.Stop()
+
+	var lifetimeC <-chan time.Time
+
+	if s.maxConnectionLifetime > 0 {
+		lifetimeTimer := time.NewTimer(s.maxConnectionLifetime)
+		defer lifetimeTimer.Stop()
+
+		lifetimeC = lifetimeTimer.C
+	}
 
 	done := make(chan error, 1)
 
+	// Replies to pending live healthcheck probes never arrive once the connection is closed
+	defer s.probes.failAll(errRedisProbeConnectionLost)
+
+	// Replies read by receiving goroutines of the previous connections (if any are still alive) are discarded
+	gen := atomic.AddUint64(&s.generation, 1)
+
+	atomic.StoreInt32(&s.subscribeBusyAttempts, 0)
+
+	s.wg.Add(1)
 	go func() {
-		for {
-			switch v := psc.Receive().(type) {
-			case redis.Message:
-				s.log.Debugf("Incoming pubsub message from Redis: %s", v.Data)
-				s.node.HandlePubSub(v.Data)
-			case redis.Subscription:
-				s.log.Infof("Subscribed to Redis channel: %s\n", v.Channel)
-			case error:
-				s.log.Errorf("Redis subscription error: %v", v)
-				done <- v
-			}
-		}
+		defer s.wg.Done()
+
+		pprof.Do(context.Background(), redisProfilingLabels("receive", s.channel), func(context.Context) {
+			s.receiveGeneration(psc, done, gen)
+		})
 	}()
 
+	s.touchReply(time.Now())
+
 	ticker := time.NewTicker(s.pingInterval * time.Second)
 	defer ticker.Stop()
 
+	retryTicker := time.NewTicker(redisSubscribeRetryInterval)
+	defer retryTicker.Stop()
+
+	var stalledC <-chan time.Time
+
+	if s.commandTimeout > 0 {
+		stalledTicker := time.NewTicker(s.commandTimeout)
+		defer stalledTicker.Stop()
+
+		stalledC = stalledTicker.C
+	}
+
 loop:
 	for err == nil {
 		select {
-		case <-ticker.C:
-			if err = psc.Ping(""); err != nil {
+		case <-s.shutdownCh:
+			s.logger().Debug("Unsubscribing from Redis channels")
+
+			// The connection could be already half-closed (e.g., broken pipe), so confirmation never arrives
+			if err := s.unsubscribeAll(psc); err != nil {
+				s.logger().Debugf("Failed to unsubscribe from Redis channels, closing the connection: %v", err)
+				psc.Close() //nolint:errcheck
+				<-done
+				return nil
+			}
+
+			select {
+			case <-done:
+			case <-time.After(redisUnsubscribeTimeout):
+				s.logger().Warn("Timed out waiting for Redis unsubscribe confirmation")
+			}
+
+			return nil
+		case now := <-ticker.C:
+			if s.keepaliveTimedOut(now) {
+				s.metrics.CounterIncrement(metricsRedisKeepaliveFailures)
+				s.logger().Warnf("No replies from Redis for %d keepalive intervals, reconnecting", redisKeepaliveMissedIntervals)
+				err = errRedisKeepaliveTimeout
+				break loop
+			}
+
+			if err = s.sendKeepalive(psc); err != nil {
+				s.metrics.CounterIncrement(metricsRedisKeepaliveFailures)
+				s.logger().Warnf("Redis keepalive (%s) failed, reconnecting: %v", s.keepaliveMode, err)
+				break loop
+			}
+		case <-lifetimeC:
+			s.logger().Infof("Redis connection max lifetime (%s) reached, reconnecting", s.maxConnectionLifetime)
+			err = errRedisConnectionRotated
+			break loop
+		case <-stableTimer.C:
+			s.ResetReconnectAttempts()
+		case <-s.channelsChangedCh:
+			if err = s.syncSubscriptions(psc); err != nil {
+				s.logger().Warnf("Failed to update Redis subscriptions, reconnecting: %v", err)
+				break loop
+			}
+		case <-stalledC:
+			// Replies are not read while delivery is paused in the block mode
+			if channel, ok := s.subscriptions.stalled(s.commandTimeout); ok && !s.Paused() {
+				s.metrics.CounterIncrement(metricsRedisSubscribeFailures)
+				s.logger().Warnf("Redis hasn't confirmed subscription to %s in %s, reconnecting", channel, s.commandTimeout)
+				err = errRedisSubscribeTimeout
+				break loop
+			}
+		case <-retryTicker.C:
+			if err = s.retryFailedSubscriptions(psc); err != nil {
+				s.logger().Warnf("Failed to retry Redis subscriptions, reconnecting: %v", err)
+				break loop
+			}
+		case <-s.subscribeRetryCh:
+			if err = s.retryFailedSubscriptions(psc); err != nil {
+				s.logger().Warnf("Failed to retry Redis subscriptions, reconnecting: %v", err)
+				break loop
+			}
+		case token := <-s.probeCh:
+			if err = psc.Ping(token); err != nil {
+				s.logger().Warnf("Redis live healthcheck ping failed, reconnecting: %v", err)
 				break loop
 			}
 		case err := <-done:
@@ -266,11 +1560,365 @@ loop:
 		}
 	}
 
-	psc.Unsubscribe() //nolint:errcheck
-	return <-done
+	// Make the receiving goroutine exit
+	psc.Close() //nolint:errcheck
+	<-done
+
+	return err
+}
+
+// receive reads messages from the pubsub connection until an error occurs
+// or all the channels are unsubscribed
+func (s *RedisSubscriber) receive(psc redis.PubSubConn, done chan error) {
+	s.receiveGeneration(psc, done, atomic.LoadUint64(&s.generation))
 }
 
+// receiveGeneration reads messages from the pubsub connection of the specified generation;
+// it stops as soon as a newer connection is established
+func (s *RedisSubscriber) receiveGeneration(psc redis.PubSubConn, done chan error, gen uint64) {
+	for {
+		reply := s.receiveReply(psc)
+
+		if atomic.LoadUint64(&s.generation) != gen {
+			s.logger().Debugf("Discarded Redis reply received via a stale connection: %v", reply)
+			s.reportDone(done, errRedisStaleConnection)
+			return
+		}
+
+		s.touchReply(time.Now())
+
+		switch v := reply.(type) {
+		case redis.Message:
+			s.receiveMessage(v.Channel, v.Data)
+		case redis.Pong:
+			// Keepalive pings have no payload, live healthcheck probes have unique tokens
+			if v.Data != "" {
+				s.probes.resolve(v.Data)
+			}
+		case redis.Subscription:
+			// Keepalive subscriptions are not tracked (see subscribe-noop keepalive mode)
+			if v.Channel == redisKeepaliveChannel && v.Count > 0 {
+				continue
+			}
+
+			if v.Kind == "subscribe" || v.Kind == "psubscribe" || v.Kind == "ssubscribe" {
+				elapsed := s.subscriptions.confirm(v.Channel)
+				s.logger().WithField("duration", elapsed).Infof("Subscribed to Redis channel: %s", v.Channel)
+				s.emitEvent(RedisEvent{Kind: RedisEventSubscribed, Channel: v.Channel})
+
+				if v.Channel == s.channel {
+					s.logRecovery()
+					s.poller.Stop()
+
+					if gen, ok := s.replayer.TakePending(); ok {
+						s.wg.Add(1)
+						go s.replayStaged(gen)
+					}
+
+					if s.marker != nil {
+						s.wg.Add(1)
+						go s.publishMarker()
+					}
+				}
+
+				if s.subscribedHandler != nil {
+					// Run callback in the background to not block messages delivery
+					go s.subscribedHandler(v.Channel)
+				}
+			} else {
+				s.logger().Debugf("Unsubscribed from Redis channel: %s", v.Channel)
+
+				// Redis unsubscribes clients from sharded channels when slots are migrated,
+				// so we must reconnect to the new owner
+				if v.Kind == "sunsubscribe" && !s.stopped() && s.subscriptions.tracked(v.Channel) {
+					s.logger().Warnf("Redis sharded channel %s has been moved, reconnecting", v.Channel)
+					s.reportDone(done, errRedisShardMoved)
+					return
+				}
+			}
+
+			if v.Count == 0 {
+				s.reportDone(done, nil)
+				return
+			}
+		case redis.Error:
+			// Error reply to a SUBSCRIBE command (e.g., no permissions for the channel):
+			// keep the connection for the other channels and retry later
+			if channel, ok := s.subscriptions.fail(); ok {
+				s.metrics.CounterIncrement(metricsRedisSubscribeFailures)
+
+				if s.handleSubscribeError(channel, v, done) {
+					continue
+				}
+
+				return
+			}
+
+			s.metrics.CounterIncrement(metricsRedisReceiveFailures)
+			s.logger().Errorf("Redis subscription error: %v", v)
+			s.reportDone(done, v)
+			return
+		case error:
+			s.logReceiveError(v)
+			s.reportDone(done, v)
+			return
+		}
+	}
+}
+
+// reportDone passes the receive result to the listener without blocking:
+// if the listener has already returned (e.g., on forced shutdown) and nobody reads the channel,
+// the result is discarded, so the receiving goroutine doesn't leak
+func (s *RedisSubscriber) reportDone(done chan error, err error) {
+	select {
+	case done <- err:
+	default:
+		s.logger().Debugf("Redis receive result discarded: %v", err)
+	}
+}
+
+// unsubscribeAll releases both plain and pattern subscriptions.
+// The receiving goroutine stops as soon as all the subscriptions are confirmed to be released.
+func (s *RedisSubscriber) unsubscribeAll(psc redis.PubSubConn) error {
+	if s.sharded {
+		if err := psc.Conn.Send("SUNSUBSCRIBE"); err != nil {
+			return err
+		}
+
+		return psc.Conn.Flush()
+	}
+
+	if err := psc.Unsubscribe(); err != nil {
+		return err
+	}
+
+	return psc.PUnsubscribe()
+}
+
+func (s *RedisSubscriber) receiveReply(psc redis.PubSubConn) interface{} {
+	if s.sharded {
+		return receiveSharded(psc)
+	}
+
+	return psc.Receive()
+}
+
+func (s *RedisSubscriber) channels() []string {
+	channels := s.regularChannels()
+
+	// Tapped channels are only subscribed to if they're not subscribed otherwise
+	for _, tapped := range s.taps.channels() {
+		if !containsString(channels, tapped) {
+			channels = append(channels, tapped)
+		}
+	}
+
+	return channels
+}
+
+// regularChannels returns the channels subscribed to for delivering messages to the handler
+func (s *RedisSubscriber) regularChannels() []string {
+	channels := []string{s.channel}
+
+	// The commands connection subscriber uses the internal channel as the main one
+	if s.internalChannel != "" && s.internalChannel != s.channel {
+		channels = append(channels, s.internalChannel)
+	}
+
+	channels = append(channels, s.keyeventChannels...)
+
+	return append(channels, s.dynamicChannelsList()...)
+}
+
+// receiveMessage processes the message received from Redis
+func (s *RedisSubscriber) receiveMessage(channel string, data []byte) {
+	channel = s.rewriteChannel(channel)
+
+	if s.tapMessage(channel, data) {
+		return
+	}
+
+	s.metrics.CounterIncrement(s.receivedMsgMetric)
+	s.metrics.CounterAdd(s.receivedBytesMetric, uint64(len(data)))
+	atomic.AddInt64(&s.totals.received, 1)
+	atomic.AddInt64(&s.receivedSinceEvent, 1)
+
+	if s.isMarker(channel, data) {
+		return
+	}
+
+	if s.activity != nil {
+		s.activity.touch(channel)
+	}
+
+	s.tee.Send(channel, data)
+	s.sampleMessage(channel, data)
+
+	if !s.awaitDelivery() {
+		s.drop(channel, data, "paused")
+		return
+	}
+
+	if s.replayer.Stage(channel, data) {
+		return
+	}
+
+	s.accept(channel, data)
+}
+
+// InjectBroadcast processes the message as if it has been received from the Redis channel
+// (all the receive path features apply: pause, envelopes, deduplication, dispatching, etc.).
+//
+// It's intended for tests only (e.g., to emulate broadcasts without running Redis).
+// If the subscriber hasn't been started, the message is delivered to the handler synchronously.
+func (s *RedisSubscriber) InjectBroadcast(channel string, data []byte) {
+	s.receiveMessage(channel, data)
+}
+
+// accept unwraps the message envelope, skips duplicates, repeated and expired messages and dispatches the message
+func (s *RedisSubscriber) accept(channel string, data []byte) {
+	// Keyspace notifications are not broadcasts
+	if s.isKeyeventChannel(channel) {
+		s.dispatch(channel, data)
+		return
+	}
+
+	if s.epochs != nil && channel != s.internalChannel {
+		var ok bool
+
+		if data, ok = s.unwrapEnvelope(channel, data); !ok {
+			return
+		}
+	}
+
+	if s.dedup != nil && channel != s.internalChannel && s.isDuplicate(data) {
+		return
+	}
+
+	if s.coalescer != nil && channel != s.internalChannel && s.isCoalesced(channel, data) {
+		return
+	}
+
+	if s.maxAgeKey != "" && s.maxAge > 0 && channel != s.internalChannel && s.isExpired(data) {
+		s.drop(channel, data, "expired")
+		return
+	}
+
+	s.dispatch(channel, data)
+}
+
+// dispatch passes the message to the dispatcher (or handles it right away if the subscriber hasn't been started)
+func (s *RedisSubscriber) dispatch(channel string, data []byte) {
+	if s.dispatcher == nil {
+		s.handleMessage(channel, data)
+		return
+	}
+
+	s.dispatcher.Dispatch(channel, data)
+}
+
+func (s *RedisSubscriber) handleDispatchOverflow(policy string, msg redisMessage) {
+	switch policy {
+	case dispatchOverflowDropNew:
+		s.metrics.CounterIncrement(metricsRedisDispatchDroppedNew)
+		s.drop(msg.channel, msg.data, "dispatch_queue_full")
+	case dispatchOverflowDropOldest:
+		s.metrics.CounterIncrement(metricsRedisDispatchDroppedOldest)
+		s.drop(msg.channel, msg.data, "dispatch_queue_evicted")
+	default:
+		s.metrics.CounterIncrement(metricsRedisDispatchBlocked)
+	}
+}
+
+func (s *RedisSubscriber) handleMessage(channel string, data []byte) {
+	s.handleMessageAt(channel, data, time.Now())
+}
+
+func (s *RedisSubscriber) handleMessageAt(channel string, data []byte, receivedAt time.Time) {
+	internal := s.internalChannel != "" && channel == s.internalChannel
+
+	if internal {
+		s.metrics.CounterIncrement(metricsRedisControlMsg)
+	}
+
+	if s.logger().IsDebug() {
+		if internal {
+			s.messageLog(data).Debugf("Incoming control message from Redis: %s", data)
+		} else {
+			s.messageLog(data).Debugf("Incoming pubsub message from Redis: %s", data)
+		}
+	}
+
+	node, ok := s.handlerOrHold(channel, data, receivedAt)
+
+	if !ok {
+		return
+	}
+
+	s.deliver(node, channel, data, receivedAt)
+}
+
+// deliver passes the message to the handler
+func (s *RedisSubscriber) deliver(node Handler, channel string, data []byte, receivedAt time.Time) {
+	if s.internalChannel != "" && channel == s.internalChannel {
+		if handler, ok := node.(CommandHandler); ok {
+			handler.HandlePubSubCommand(data)
+		} else {
+			node.HandlePubSub(data)
+		}
+	} else if s.isKeyeventChannel(channel) {
+		if !s.deliverKeyevent(node, channel, data) {
+			return
+		}
+	} else if handler, ok := node.(CheckedHandler); ok {
+		if !s.deliverChecked(handler, channel, data) {
+			return
+		}
+	} else if handler, ok := node.(MetaHandler); ok {
+		handler.HandlePubSubWithMeta(channel, data, receivedAt)
+	} else {
+		node.HandlePubSub(data)
+	}
+
+	s.latencies.record(time.Since(receivedAt))
+	s.metrics.CounterIncrement(metricsRedisHandledMsg)
+	atomic.AddInt64(&s.totals.handled, 1)
+}
+
+// checkRedisScheme verifies that the URL scheme is supported by the Redis client
+// (url.Parse accepts any scheme, so the misconfiguration would only surface as dial failures otherwise)
+func checkRedisScheme(uri *url.URL) error {
+	for _, scheme := range redisSupportedSchemes {
+		if uri.Scheme == scheme {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("unsupported Redis URL scheme: %q (accepted schemes: %s)", uri.Scheme, strings.Join(redisSupportedSchemes, ", "))
+}
+
+// nextRetry returns a delay before the next reconnect attempt.
+// The first attempt is performed immediately; the backoff starts from the second one.
 func nextRetry(step int) time.Duration {
-	secs := (step * step) + (rand.Intn(step*4) * (step + 1)) // #nosec
+	return nextRetryWithRand(step, rand.Intn)
+}
+
+// nextRetryWithRand is the same as nextRetry but uses the provided random numbers generator
+// (so the schedule could be reproduced in tests)
+func nextRetryWithRand(step int, intn func(n int) int) time.Duration {
+	if step <= 1 {
+		return 0
+	}
+
+	step--
+
+	// Bound the jitter, so it stays reasonable regardless of the number of attempts
+	jitterStep := step
+
+	if jitterStep > maxRetryJitterStep {
+		jitterStep = maxRetryJitterStep
+	}
+
+	secs := (step * step) + (intn(jitterStep*4) * (jitterStep + 1))
 	return time.Duration(secs) * time.Second
 }
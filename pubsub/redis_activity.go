@@ -0,0 +1,93 @@
+package pubsub
+
+import (
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	metricsRedisInactiveChannels = "redis_inactive_channels_total"
+)
+
+// redisActivity counts messages received from the channels expected to be active
+// (the set of channels is fixed, so counters could be updated without locking)
+type redisActivity struct {
+	counters map[string]*int64
+	// Whether the channel was subscribed and had no messages during the previous window
+	inactive map[string]bool
+}
+
+func newRedisActivity(raw string, defaultChannel string) *redisActivity {
+	a := &redisActivity{counters: make(map[string]*int64), inactive: make(map[string]bool)}
+
+	for _, channel := range strings.Split(raw, ",") {
+		if channel = strings.TrimSpace(channel); channel != "" {
+			a.counters[channel] = new(int64)
+		}
+	}
+
+	if len(a.counters) == 0 {
+		a.counters[defaultChannel] = new(int64)
+	}
+
+	return a
+}
+
+func (a *redisActivity) touch(channel string) {
+	if counter, ok := a.counters[channel]; ok {
+		atomic.AddInt64(counter, 1)
+	}
+}
+
+// watchActivity checks that the expected channels receive messages within every window
+func (s *RedisSubscriber) watchActivity() {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(s.activityWindow)
+	defer ticker.Stop()
+
+	// Channels must stay subscribed for the whole window to be checked
+	subscribed := s.subscriptions.snapshot()
+
+	for {
+		select {
+		case <-s.shutdownCh:
+			return
+		case <-ticker.C:
+			subscribed = s.checkActivity(subscribed)
+		}
+	}
+}
+
+// checkActivity reports channels that have been subscribed during the whole window but received no messages,
+// and returns the current subscription states to be used for the next check
+func (s *RedisSubscriber) checkActivity(prev map[string]string) map[string]string {
+	current := s.subscriptions.snapshot()
+
+	for channel, counter := range s.activity.counters {
+		received := atomic.SwapInt64(counter, 0)
+
+		if received > 0 {
+			if s.activity.inactive[channel] {
+				s.logger().Infof("Redis channel %s is active again", channel)
+				delete(s.activity.inactive, channel)
+			}
+
+			continue
+		}
+
+		if prev[channel] != RedisChannelSubscribed || current[channel] != RedisChannelSubscribed {
+			continue
+		}
+
+		s.metrics.CounterIncrement(metricsRedisInactiveChannels)
+
+		if !s.activity.inactive[channel] {
+			s.logger().Warnf("No messages received from Redis channel %s in %s", channel, s.activityWindow)
+			s.activity.inactive[channel] = true
+		}
+	}
+
+	return current
+}
@@ -0,0 +1,22 @@
+package pubsub
+
+import (
+	"bufio"
+	"net"
+)
+
+// bufferedConn reads from the underlying connection using a buffer of the specified size.
+// The Redis client uses a fixed-size (4KB) read buffer; wrapping the connection
+// allows us to read large payloads with fewer syscalls.
+type bufferedConn struct {
+	net.Conn
+	reader *bufio.Reader
+}
+
+func newBufferedConn(conn net.Conn, size int) net.Conn {
+	return &bufferedConn{Conn: conn, reader: bufio.NewReaderSize(conn, size)}
+}
+
+func (c *bufferedConn) Read(p []byte) (int, error) {
+	return c.reader.Read(p)
+}
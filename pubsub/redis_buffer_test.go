@@ -0,0 +1,83 @@
+package pubsub
+
+import (
+	"fmt"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// streamConn is a net.Conn infinitely streaming the same payload
+// and counting Read calls (i.e., syscalls for a real connection)
+type streamConn struct {
+	net.Conn
+	payload []byte
+	offset  int
+	reads   int
+}
+
+func (c *streamConn) Read(p []byte) (int, error) {
+	c.reads++
+
+	n := copy(p, c.payload[c.offset:])
+	c.offset = (c.offset + n) % len(c.payload)
+
+	return n, nil
+}
+
+func (c *streamConn) Write(p []byte) (int, error)        { return len(p), nil }
+func (c *streamConn) Close() error                       { return nil }
+func (c *streamConn) SetDeadline(t time.Time) error      { return nil }
+func (c *streamConn) SetReadDeadline(t time.Time) error  { return nil }
+func (c *streamConn) SetWriteDeadline(t time.Time) error { return nil }
+
+func respMessage(channel string, data string) []byte {
+	return []byte(fmt.Sprintf("*3\r\n$7\r\nmessage\r\n$%d\r\n%s\r\n$%d\r\n%s\r\n", len(channel), channel, len(data), data))
+}
+
+func TestBufferedConn(t *testing.T) {
+	payload := strings.Repeat("a", 64*1024)
+	raw := &streamConn{payload: respMessage("__anycable__", payload)}
+
+	psc := redis.PubSubConn{Conn: redis.NewConn(newBufferedConn(raw, 128*1024), 0, 0)}
+
+	msg, ok := psc.Receive().(redis.Message)
+	require.True(t, ok)
+
+	assert.Equal(t, payload, string(msg.Data))
+	assert.Equal(t, 1, raw.reads)
+}
+
+func BenchmarkRedisReadBufferSize(b *testing.B) {
+	payload := respMessage("__anycable__", strings.Repeat("a", 32*1024))
+
+	for _, size := range []int{0, 64 * 1024, 256 * 1024} {
+		b.Run(fmt.Sprintf("size=%d", size), func(b *testing.B) {
+			raw := &streamConn{payload: payload}
+
+			var conn net.Conn = raw
+
+			if size > 0 {
+				conn = newBufferedConn(raw, size)
+			}
+
+			psc := redis.PubSubConn{Conn: redis.NewConn(conn, 0, 0)}
+
+			b.SetBytes(int64(len(payload)))
+			b.ResetTimer()
+
+			for i := 0; i < b.N; i++ {
+				if _, ok := psc.Receive().(redis.Message); !ok {
+					b.Fatal("unexpected reply")
+				}
+			}
+
+			b.ReportMetric(float64(raw.reads)/float64(b.N), "reads/msg")
+		})
+	}
+}
@@ -0,0 +1,97 @@
+package pubsub
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/anycable/anycable-go/utils"
+	"github.com/gomodule/redigo/redis"
+)
+
+// ErrRedisVersionTooOld is reported when the Redis server is older than the configured minimum version
+var ErrRedisVersionTooOld = errors.New("Redis server version is older than required") //nolint:stylecheck
+
+// parseRedisVersion extracts the redis_version field from the INFO command output
+func parseRedisVersion(info string) (string, utils.Version, error) {
+	scanner := bufio.NewScanner(strings.NewReader(info))
+
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+
+		if !strings.HasPrefix(line, "redis_version:") {
+			continue
+		}
+
+		raw := strings.TrimPrefix(line, "redis_version:")
+
+		version, err := utils.ParseVersion(raw)
+
+		if err != nil {
+			return raw, version, fmt.Errorf("malformed Redis version: %s", raw)
+		}
+
+		return raw, version, nil
+	}
+
+	return "", utils.Version{}, fmt.Errorf("redis_version is missing in INFO output")
+}
+
+// unsupportedFeatures returns the configured features the Redis server version doesn't support
+func (s *RedisSubscriber) unsupportedFeatures(version utils.Version, redisURL string) []string {
+	var features []string
+
+	if s.sharded && version.Compare(utils.Version{Major: 7}) < 0 {
+		features = append(features, "sharded pub/sub (SSUBSCRIBE) requires Redis 7.0+, disable --redis_sharded")
+	}
+
+	if uri, err := url.Parse(redisURL); err == nil && uri.User != nil && uri.User.Username() != "" && version.Compare(utils.Version{Major: 6}) < 0 {
+		features = append(features, "ACL users require Redis 6.0+, remove the username from the Redis URL")
+	}
+
+	return features
+}
+
+// checkCapabilities logs the Redis server version and warns about the configured features
+// not supported by the server. Failures are not fatal (e.g., INFO could be disabled via ACL or a proxy)
+// unless the minimum Redis version is configured: the version must be verified in this case.
+func (s *RedisSubscriber) checkCapabilities(c redis.Conn, redisURL string) error {
+	info, err := redis.String(redis.DoWithTimeout(c, s.commandTimeout, "INFO", "server"))
+
+	if err != nil {
+		if s.minVersion != nil {
+			return fmt.Errorf("failed to verify Redis server version: %w", err)
+		}
+
+		s.logger().Debugf("Failed to retrieve Redis server info: %v", err)
+		return nil
+	}
+
+	raw, version, err := parseRedisVersion(info)
+
+	if err != nil {
+		if s.minVersion != nil {
+			return fmt.Errorf("failed to verify Redis server version: %w", err)
+		}
+
+		s.logger().Debugf("Failed to detect Redis version: %v", err)
+		return nil
+	}
+
+	if raw != s.serverVersion {
+		s.serverVersion = raw
+		s.logger().Infof("Redis server version: %s", raw)
+	}
+
+	if s.minVersion != nil && version.Compare(*s.minVersion) < 0 {
+		return fmt.Errorf("%w: %s < %s", ErrRedisVersionTooOld, raw, s.minVersionRaw)
+	}
+
+	for _, feature := range s.unsupportedFeatures(version, redisURL) {
+		s.logger().Warnf("Unsupported by Redis %s: %s", raw, feature)
+	}
+
+	return nil
+}
@@ -0,0 +1,22 @@
+package pubsub
+
+// ChannelRewrite maps the name of the Redis channel a message has been received from
+// to the name the subscriber should use (e.g., to strip a legacy prefix)
+type ChannelRewrite func(channel string) string
+
+// SetChannelRewrite sets a function to rewrite channel names of received messages.
+// The rewritten name is used for everything downstream: internal and keyspace channels detection,
+// metrics, dead letters, and metadata passed to the handler.
+// Must be called before Start.
+func (s *RedisSubscriber) SetChannelRewrite(rewrite ChannelRewrite) {
+	s.channelRewrite = rewrite
+}
+
+// rewriteChannel returns the channel name with the rewrite applied (if any)
+func (s *RedisSubscriber) rewriteChannel(channel string) string {
+	if s.channelRewrite == nil {
+		return channel
+	}
+
+	return s.channelRewrite(channel)
+}
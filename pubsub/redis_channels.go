@@ -0,0 +1,250 @@
+package pubsub
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+)
+
+const (
+	// Redis channel subscription states
+	RedisChannelPending    = "pending"
+	RedisChannelSubscribed = "subscribed"
+	RedisChannelFailed     = "failed"
+
+	// How often to retry failed channel subscriptions
+	redisSubscribeRetryInterval = 30 * time.Second
+
+	defaultRedisSubscribeBusyRetries  = 5
+	defaultRedisSubscribeBusyInterval = 1
+)
+
+// redisSubscriptions tracks per-channel subscription states.
+// Redis confirms (or rejects) subscriptions in the order they were requested,
+// so we keep a queue of pending channels to match error replies with channels.
+type redisSubscriptions struct {
+	command   string
+	mu        sync.Mutex
+	states    map[string]string
+	pending   []string
+	startedAt map[string]time.Time
+}
+
+func newRedisSubscriptions(command string) *redisSubscriptions {
+	return &redisSubscriptions{command: command, states: make(map[string]string), startedAt: make(map[string]time.Time)}
+}
+
+// subscribe sends a separate SUBSCRIBE (or SSUBSCRIBE) command for each channel,
+// so a failure for one channel doesn't affect the others
+func (rs *redisSubscriptions) subscribe(psc redis.PubSubConn, channels []string) error {
+	for _, channel := range channels {
+		rs.mu.Lock()
+		rs.states[channel] = RedisChannelPending
+		rs.pending = append(rs.pending, channel)
+		rs.startedAt[channel] = time.Now()
+		rs.mu.Unlock()
+
+		if err := psc.Conn.Send(rs.command, channel); err != nil {
+			return err
+		}
+	}
+
+	return psc.Conn.Flush()
+}
+
+// unsubscribe sends UNSUBSCRIBE (or SUNSUBSCRIBE) for the channels and stops tracking them
+func (rs *redisSubscriptions) unsubscribe(psc redis.PubSubConn, channels []string) error {
+	rs.mu.Lock()
+
+	for _, channel := range channels {
+		delete(rs.states, channel)
+		delete(rs.startedAt, channel)
+		rs.removePending(channel)
+	}
+
+	rs.mu.Unlock()
+
+	args := make([]interface{}, len(channels))
+
+	for i, channel := range channels {
+		args[i] = channel
+	}
+
+	if err := psc.Conn.Send(unsubscribeCommand(rs.command), args...); err != nil {
+		return err
+	}
+
+	return psc.Conn.Flush()
+}
+
+// tracked returns true if the channel is expected to be subscribed
+func (rs *redisSubscriptions) tracked(channel string) bool {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+
+	_, ok := rs.states[channel]
+
+	return ok
+}
+
+// confirm marks the channel as subscribed and returns the time elapsed since the subscribe command was sent
+func (rs *redisSubscriptions) confirm(channel string) time.Duration {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+
+	rs.removePending(channel)
+	rs.states[channel] = RedisChannelSubscribed
+
+	var elapsed time.Duration
+
+	if startedAt, ok := rs.startedAt[channel]; ok {
+		elapsed = time.Since(startedAt)
+		delete(rs.startedAt, channel)
+	}
+
+	return elapsed
+}
+
+// fail marks the oldest pending channel as failed and returns it
+func (rs *redisSubscriptions) fail() (string, bool) {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+
+	if len(rs.pending) == 0 {
+		return "", false
+	}
+
+	channel := rs.pending[0]
+	rs.pending = rs.pending[1:]
+	rs.states[channel] = RedisChannelFailed
+	delete(rs.startedAt, channel)
+
+	return channel, true
+}
+
+// stalled returns the first channel which subscription hasn't been confirmed for longer than the timeout
+func (rs *redisSubscriptions) stalled(timeout time.Duration) (string, bool) {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+
+	for _, channel := range rs.pending {
+		if startedAt, ok := rs.startedAt[channel]; ok && time.Since(startedAt) > timeout {
+			return channel, true
+		}
+	}
+
+	return "", false
+}
+
+// failed returns the list of channels failed to subscribe to
+func (rs *redisSubscriptions) failed() []string {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+
+	var channels []string
+
+	for channel, state := range rs.states {
+		if state == RedisChannelFailed {
+			channels = append(channels, channel)
+		}
+	}
+
+	return channels
+}
+
+// reset forgets all the states (e.g., when the connection is closed)
+func (rs *redisSubscriptions) reset() {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+
+	rs.states = make(map[string]string)
+	rs.pending = nil
+	rs.startedAt = make(map[string]time.Time)
+}
+
+// snapshot returns a copy of the channel states (or nil if there are no subscriptions)
+func (rs *redisSubscriptions) snapshot() map[string]string {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+
+	if len(rs.states) == 0 {
+		return nil
+	}
+
+	states := make(map[string]string, len(rs.states))
+
+	for channel, state := range rs.states {
+		states[channel] = state
+	}
+
+	return states
+}
+
+func (rs *redisSubscriptions) removePending(channel string) {
+	for i, pending := range rs.pending {
+		if pending == channel {
+			rs.pending = append(rs.pending[:i], rs.pending[i+1:]...)
+			return
+		}
+	}
+}
+
+// handleSubscribeError logs the failed subscription to the channel, which is retried later on the same connection.
+// Transient server-busy errors (e.g., LOADING right after a restart) are retried shortly, up to the configured
+// number of times per connection; after that, the error is reported, so we reconnect with backoff.
+// Returns false if the error has been reported.
+func (s *RedisSubscriber) handleSubscribeError(channel string, err redis.Error, done chan error) bool {
+	kind, busy := redisBusyError(err)
+
+	if !busy {
+		s.logger().Errorf("Failed to subscribe to Redis channel %s, will retry in %s: %v", channel, redisSubscribeRetryInterval, err)
+		return true
+	}
+
+	attempt := int(atomic.AddInt32(&s.subscribeBusyAttempts, 1))
+
+	if attempt > s.subscribeBusyRetries {
+		s.logger().Warnf("Redis is still unavailable (%s) after %d subscribe retries, reconnecting: %v", kind, s.subscribeBusyRetries, err)
+		s.reportDone(done, err)
+		return false
+	}
+
+	if kind == "LOADING" {
+		s.logger().Infof("Redis is loading the dataset, retrying subscription to %s in %s (%d of %d)", channel, s.subscribeBusyInterval, attempt, s.subscribeBusyRetries)
+	} else {
+		s.logger().Infof("Redis is busy (%s), retrying subscription to %s in %s (%d of %d): %v", kind, channel, s.subscribeBusyInterval, attempt, s.subscribeBusyRetries, err)
+	}
+
+	// SUBSCRIBE is sent by the serve loop, since it owns writes to the subscription connection
+	time.AfterFunc(s.subscribeBusyInterval, func() {
+		select {
+		case s.subscribeRetryCh <- struct{}{}:
+		default:
+		}
+	})
+
+	return true
+}
+
+// retryFailedSubscriptions re-sends SUBSCRIBE commands for the channels failed to subscribe to
+func (s *RedisSubscriber) retryFailedSubscriptions(psc redis.PubSubConn) error {
+	failed := s.subscriptions.failed()
+
+	if len(failed) == 0 {
+		return nil
+	}
+
+	s.logger().Debugf("Retrying subscription to Redis channels: %v", failed)
+
+	return s.subscriptions.subscribe(psc, failed)
+}
+
+func unsubscribeCommand(subscribeCommand string) string {
+	if subscribeCommand == "SSUBSCRIBE" {
+		return "SUNSUBSCRIBE"
+	}
+
+	return "UNSUBSCRIBE"
+}
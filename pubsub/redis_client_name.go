@@ -0,0 +1,42 @@
+package pubsub
+
+import (
+	"strings"
+
+	"github.com/gomodule/redigo/redis"
+)
+
+const (
+	// Client name suffix for auxiliary (pool) connections
+	redisClientNameAux = "aux"
+	// Client name suffix for health check connections
+	redisClientNameHealth = "health"
+)
+
+// clientName returns the connection name to set via CLIENT SETNAME (or an empty string if names are disabled).
+// The suffix describes the connection purpose: the list of channels for the subscription connection,
+// so CLIENT LIST distinguishes connections serving different channels.
+func (s *RedisSubscriber) clientName(suffix string) string {
+	if s.clientNamePrefix == "" {
+		return ""
+	}
+
+	// Client names can't contain spaces and newlines
+	return strings.Map(func(r rune) rune {
+		if r <= ' ' {
+			return '_'
+		}
+
+		return r
+	}, s.clientNamePrefix+":"+suffix)
+}
+
+func (s *RedisSubscriber) clientNameOptions(suffix string) []redis.DialOption {
+	name := s.clientName(suffix)
+
+	if name == "" {
+		return nil
+	}
+
+	return []redis.DialOption{redis.DialClientName(name)}
+}
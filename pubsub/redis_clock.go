@@ -0,0 +1,20 @@
+package pubsub
+
+import (
+	"math/rand"
+	"time"
+)
+
+// redisClock provides timers and random numbers for reconnect scheduling.
+// It's replaced in tests to reproduce reconnect schedules without real delays.
+type redisClock struct {
+	after func(d time.Duration) <-chan time.Time
+	intn  func(n int) int
+}
+
+func defaultRedisClock() redisClock {
+	return redisClock{
+		after: time.After,
+		intn:  rand.Intn, // #nosec
+	}
+}
@@ -0,0 +1,59 @@
+package pubsub
+
+import (
+	"hash/fnv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const metricsRedisCoalescedMsg = "redis_coalesced_msg_total"
+
+// redisCoalescer suppresses broadcasts identical to the previous one on the same channel within the window.
+// Only the last payload hash (FNV-1a, which is fast enough for the hot path) and size are kept per channel.
+type redisCoalescer struct {
+	window time.Duration
+	now    func() time.Time
+	mu     sync.Mutex
+	last   map[string]coalescedMessage
+}
+
+type coalescedMessage struct {
+	hash uint64
+	size int
+	at   time.Time
+}
+
+func newRedisCoalescer(window time.Duration) *redisCoalescer {
+	return &redisCoalescer{window: window, now: time.Now, last: make(map[string]coalescedMessage)}
+}
+
+// Repeated returns true if the payload is identical to the previous one on the channel received within the window;
+// otherwise, remembers it as the last one
+func (c *redisCoalescer) Repeated(channel string, data []byte) bool {
+	h := fnv.New64a()
+	h.Write(data) // nolint:errcheck
+
+	msg := coalescedMessage{hash: h.Sum64(), size: len(data), at: c.now()}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	prev, ok := c.last[channel]
+	c.last[channel] = msg
+
+	return ok && prev.hash == msg.hash && prev.size == msg.size && msg.at.Sub(prev.at) <= c.window
+}
+
+// isCoalesced returns true if the message repeats the previous one on the channel and must be suppressed
+func (s *RedisSubscriber) isCoalesced(channel string, data []byte) bool {
+	if !s.coalescer.Repeated(channel, data) {
+		return false
+	}
+
+	s.metrics.CounterIncrement(metricsRedisCoalescedMsg)
+	atomic.AddInt64(&s.totals.coalesced, 1)
+	s.logger().Debugf("Repeated Redis message from channel %s suppressed", channel)
+
+	return true
+}
@@ -0,0 +1,128 @@
+package pubsub
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/anycable/anycable-go/metrics"
+)
+
+// RedisSplitSubscriber consumes broadcasts and internal (control) commands via separate Redis connections,
+// so high-volume broadcast traffic can't delay commands (e.g., remote disconnects).
+//
+// Every connection has its own RedisSubscriber (with its own reconnect logic and health);
+// the subscriber fails as soon as any of them gives up.
+type RedisSplitSubscriber struct {
+	broadcasts *RedisSubscriber
+	commands   *RedisSubscriber
+	doneOnce   sync.Once
+}
+
+// RedisSplitStatus describes the states of the broadcasts and commands connections
+type RedisSplitStatus struct {
+	Broadcasts RedisStatus
+	Commands   RedisStatus
+}
+
+var _ Subscriber = (*RedisSplitSubscriber)(nil)
+
+// NewRedisSplitSubscriber returns new RedisSplitSubscriber; config.InternalChannel must be set
+func NewRedisSplitSubscriber(node Handler, metrics metrics.Instrumenter, config *RedisConfig) (*RedisSplitSubscriber, error) {
+	if config.InternalChannel == "" {
+		return nil, errors.New("Redis commands connection requires the internal channel") //nolint:stylecheck
+	}
+
+	broadcastsConfig := *config
+	broadcastsConfig.InternalChannel = ""
+
+	broadcasts := newRedisSubscriber(node, metrics, &broadcastsConfig, "broadcasts")
+	broadcasts.log = broadcasts.log.WithField("connection", "broadcasts")
+
+	commands := newRedisSubscriber(node, metrics, commandsConfig(config), "commands")
+	commands.log = commands.log.WithField("connection", "commands")
+
+	return &RedisSplitSubscriber{broadcasts: broadcasts, commands: commands}, nil
+}
+
+// commandsConfig returns the config for the commands connection subscriber:
+// the internal channel is the only one subscribed, and broadcast-only features are disabled
+func commandsConfig(config *RedisConfig) *RedisConfig {
+	commands := *config
+
+	commands.Channel = config.InternalChannel
+	commands.KeyspaceEvents = ""
+	commands.ChannelsFile = ""
+	commands.ReplayKey = ""
+	commands.TeePath = ""
+	commands.TeeSocket = ""
+	commands.DeadLetterKey = ""
+	commands.CrashDumpPath = ""
+	commands.MarkerPayload = ""
+	commands.ActivityWindow = 0
+	commands.PollKey = ""
+	commands.SystemdWatchdog = false
+
+	return &commands
+}
+
+// Start starts both subscribers; the first error reported by any of them is passed to done
+func (s *RedisSplitSubscriber) Start(done chan (error)) error {
+	if err := s.commands.Start(s.forward(done)); err != nil {
+		return fmt.Errorf("failed to start Redis commands subscriber: %w", err)
+	}
+
+	if err := s.broadcasts.Start(s.forward(done)); err != nil {
+		s.commands.Shutdown() // nolint:errcheck
+		return err
+	}
+
+	return nil
+}
+
+func (s *RedisSplitSubscriber) forward(done chan (error)) chan (error) {
+	ch := make(chan error, 1)
+
+	go func() {
+		err := <-ch
+		s.doneOnce.Do(func() { done <- err })
+	}()
+
+	return ch
+}
+
+// Shutdown shuts down both subscribers
+func (s *RedisSplitSubscriber) Shutdown() error {
+	var wg sync.WaitGroup
+
+	for _, subscriber := range []*RedisSubscriber{s.broadcasts, s.commands} {
+		wg.Add(1)
+
+		go func(subscriber *RedisSubscriber) {
+			defer wg.Done()
+			subscriber.Shutdown() // nolint:errcheck
+		}(subscriber)
+	}
+
+	wg.Wait()
+
+	return nil
+}
+
+// Status returns the states of both connections
+func (s *RedisSplitSubscriber) Status() RedisSplitStatus {
+	return RedisSplitStatus{
+		Broadcasts: s.broadcasts.Status(),
+		Commands:   s.commands.Status(),
+	}
+}
+
+// Ready returns true if both connections are subscribed
+func (s *RedisSplitSubscriber) Ready() bool {
+	return s.broadcasts.Ready() && s.commands.Ready()
+}
+
+// Live returns true if both subscribers are alive
+func (s *RedisSplitSubscriber) Live() bool {
+	return s.broadcasts.Live() && s.commands.Live()
+}
@@ -0,0 +1,174 @@
+package pubsub
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/anycable/anycable-go/metrics"
+	"github.com/gomodule/redigo/redis"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewRedisSplitSubscriber(t *testing.T) {
+	config := NewRedisConfig()
+	config.CommandsConnection = true
+
+	_, err := NewRedisSplitSubscriber(&commandHandler{}, metrics.NoopMetrics{}, &config)
+	assert.Error(t, err)
+
+	config.InternalChannel = "__anycable_internal__"
+	config.PollKey = "__anycable_poll__"
+
+	subscriber, err := NewRedisSplitSubscriber(&commandHandler{}, metrics.NoopMetrics{}, &config)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"__anycable__"}, subscriber.broadcasts.channels())
+	assert.Equal(t, []string{"__anycable_internal__"}, subscriber.commands.channels())
+	assert.True(t, subscriber.broadcasts.poller.Enabled())
+	assert.False(t, subscriber.commands.poller.Enabled())
+}
+
+func TestRedisSplitSubscriber(t *testing.T) {
+	config := NewRedisConfig()
+	config.InternalChannel = "__anycable_internal__"
+	config.CommandsConnection = true
+
+	handler := &commandHandler{}
+	handler.On("HandlePubSub", []byte("broadcast"))
+
+	subscriber, err := NewRedisSplitSubscriber(handler, metrics.NoopMetrics{}, &config)
+	require.NoError(t, err)
+
+	broadcasts, commands := subscriber.broadcasts, subscriber.commands
+
+	commands.connect = func() error {
+		commands.InjectBroadcast("__anycable_internal__", []byte("disconnect"))
+		<-commands.shutdownCh
+		return nil
+	}
+
+	broadcasts.singleShot = true
+	broadcasts.connect = func() error {
+		broadcasts.InjectBroadcast("__anycable__", []byte("broadcast"))
+		return errors.New("connection refused")
+	}
+
+	done := make(chan error, 1)
+
+	require.NoError(t, subscriber.Start(done))
+	assert.EqualError(t, <-done, "connection refused")
+
+	status := subscriber.Status()
+	assert.Equal(t, maxReconnectAttempts, status.Broadcasts.MaxReconnectAttempts)
+	assert.Equal(t, maxReconnectAttempts, status.Commands.MaxReconnectAttempts)
+	assert.False(t, subscriber.Ready())
+
+	require.NoError(t, subscriber.Shutdown())
+
+	handler.AssertCalled(t, "HandlePubSub", []byte("broadcast"))
+	assert.Equal(t, [][]byte{[]byte("disconnect")}, handler.commands)
+}
+
+func TestRedisSplitSubscriberConnectionMetrics(t *testing.T) {
+	config := NewRedisConfig()
+	config.URL = "redis://redis-1:6379/5"
+	config.InternalChannel = "__anycable_internal__"
+	config.CommandsConnection = true
+	config.MetricsConnectionTag = true
+
+	handler := &commandHandler{}
+	handler.On("HandlePubSub", []byte("broadcast"))
+
+	m := metrics.NewMetrics(nil, 0)
+
+	subscriber, err := NewRedisSplitSubscriber(handler, m, &config)
+	require.NoError(t, err)
+
+	conn := &fakeRedisConn{reply: redisMessageReply("__anycable__", "broadcast"), limit: 3}
+	subscriber.broadcasts.receive(redis.PubSubConn{Conn: conn}, make(chan error, 1))
+
+	conn = &fakeRedisConn{reply: redisMessageReply("__anycable_internal__", "disconnect"), limit: 1}
+	subscriber.commands.receive(redis.PubSubConn{Conn: conn}, make(chan error, 1))
+
+	broadcastsTags := map[string]string{"connection": "redis-1:6379/5", "role": "broadcasts"}
+	commandsTags := map[string]string{"connection": "redis-1:6379/5", "role": "commands"}
+
+	assert.Equal(t, uint64(3), m.Counter(metrics.SeriesKey(metricsRedisReceivedMsg, broadcastsTags)).Value())
+	assert.Equal(t, uint64(1), m.Counter(metrics.SeriesKey(metricsRedisReceivedMsg, commandsTags)).Value())
+	assert.Equal(t, uint64(27), m.Counter(metrics.SeriesKey(metricsRedisReceivedBytes, broadcastsTags)).Value())
+	assert.Equal(t, uint64(10), m.Counter(metrics.SeriesKey(metricsRedisReceivedBytes, commandsTags)).Value())
+}
+
+func TestRedisSplitSubscriberBroadcastOnlyFeatures(t *testing.T) {
+	dir := t.TempDir()
+
+	config := NewRedisConfig()
+	config.InternalChannel = "__anycable_internal__"
+	config.CommandsConnection = true
+	config.TeePath = filepath.Join(dir, "tee.log")
+	config.TeeSocket = filepath.Join(dir, "tee.sock")
+	config.DeadLetterKey = "__anycable_dead__"
+
+	t.Run("Tee", func(t *testing.T) {
+		handler := &commandHandler{}
+		handler.On("HandlePubSub", []byte("broadcast"))
+
+		subscriber, err := NewRedisSplitSubscriber(handler, metrics.NoopMetrics{}, &config)
+		require.NoError(t, err)
+
+		broadcasts, commands := subscriber.broadcasts, subscriber.commands
+
+		commands.connect = func() error {
+			commands.InjectBroadcast("__anycable_internal__", []byte("disconnect"))
+			<-commands.shutdownCh
+			return nil
+		}
+
+		broadcasts.connect = func() error {
+			broadcasts.InjectBroadcast("__anycable__", []byte("broadcast"))
+			<-broadcasts.shutdownCh
+			return nil
+		}
+
+		require.NoError(t, subscriber.Start(make(chan error, 1)))
+
+		assert.NotNil(t, broadcasts.tee.socketCh)
+		assert.Nil(t, commands.tee.socketCh)
+
+		assert.Eventually(t, func() bool {
+			data, _ := os.ReadFile(config.TeePath)
+			return len(data) > 0
+		}, 3*time.Second, 10*time.Millisecond)
+
+		require.NoError(t, subscriber.Shutdown())
+
+		data, err := os.ReadFile(config.TeePath)
+		require.NoError(t, err)
+
+		lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+		require.Len(t, lines, 1)
+
+		var record teeRecord
+
+		require.NoError(t, json.Unmarshal([]byte(lines[0]), &record))
+		assert.Equal(t, "__anycable__", record.Channel)
+		assert.Equal(t, [][]byte{[]byte("disconnect")}, handler.commands)
+	})
+
+	t.Run("Dead letters", func(t *testing.T) {
+		subscriber, err := NewRedisSplitSubscriber(&commandHandler{}, metrics.NoopMetrics{}, &config)
+		require.NoError(t, err)
+
+		subscriber.broadcasts.drop("__anycable__", []byte("broadcast"), "test")
+		subscriber.commands.drop("__anycable_internal__", []byte("disconnect"), "test")
+
+		assert.Len(t, subscriber.broadcasts.deadLetters.ch, 1)
+		assert.Empty(t, subscriber.commands.deadLetters.ch)
+	})
+}
@@ -0,0 +1,94 @@
+package pubsub
+
+import (
+	"net/url"
+	"strings"
+)
+
+// Config returns a copy of the effective subscriber configuration (with defaults applied).
+// Passwords in URLs and sentinel addresses are redacted.
+func (s *RedisSubscriber) Config() RedisConfig {
+	config := s.config
+
+	config.URL = redactRedisURL(config.URL)
+	config.HealthcheckURL = redactRedisURL(config.HealthcheckURL)
+	config.SentinelFallbackURL = redactRedisURL(config.SentinelFallbackURL)
+	config.Sentinels = redactSentinels(config.Sentinels)
+
+	if config.MetricsTags != nil {
+		tags := make(map[string]string, len(config.MetricsTags))
+
+		for k, v := range config.MetricsTags {
+			tags[k] = v
+		}
+
+		config.MetricsTags = tags
+	}
+
+	return config
+}
+
+// effectiveConfig returns a copy of the config with defaults applied
+func effectiveConfig(config *RedisConfig) RedisConfig {
+	effective := *config
+
+	if effective.DispatchOverflowPolicy == "" {
+		effective.DispatchOverflowPolicy = dispatchOverflowBlock
+	}
+
+	if effective.PauseMode == "" {
+		effective.PauseMode = redisPauseBlock
+	}
+
+	if effective.KeepaliveMode == "" {
+		effective.KeepaliveMode = redisKeepalivePing
+	}
+
+	if effective.NoHandlerPolicy == "" {
+		effective.NoHandlerPolicy = redisNoHandlerDrop
+	}
+
+	if effective.LogContextKey == "" {
+		effective.LogContextKey = defaultRedisLogContextKey
+	}
+
+	if effective.LogContext == "" {
+		effective.LogContext = defaultRedisLogContext
+	}
+
+	// Without application-level keepalive, TCP keepalive is the only way to detect dead connections
+	if effective.KeepaliveMode == redisKeepaliveNone {
+		effective.TCPKeepalive = true
+	}
+
+	return effective
+}
+
+func redactRedisURL(raw string) string {
+	if raw == "" {
+		return raw
+	}
+
+	uri, err := url.Parse(raw)
+
+	if err != nil {
+		return raw
+	}
+
+	return uri.Redacted()
+}
+
+// redactSentinels redacts passwords in the comma-separated list of sentinel addresses ([:password@]host:port)
+func redactSentinels(raw string) string {
+	if raw == "" {
+		return raw
+	}
+
+	addrs := strings.Split(raw, ",")
+
+	for i, addr := range addrs {
+		addrs[i] = strings.TrimPrefix(redactRedisURL("redis://"+addr), "redis://")
+	}
+
+	return strings.Join(addrs, ",")
+}
@@ -0,0 +1,47 @@
+package pubsub
+
+import (
+	"net/url"
+)
+
+const (
+	metricsRedisReceivedBytes = "redis_received_bytes_total"
+
+	redisConnectionMetricsTag = "connection"
+	redisRoleMetricsTag       = "role"
+)
+
+// redisConnectionLabel returns the connection identity to use as a metrics tag:
+// host:port and database (or the master name and database in sentinel mode) without credentials
+func redisConnectionLabel(raw string) string {
+	uri, err := url.Parse(raw)
+
+	if err != nil || uri.Host == "" {
+		return redactRedisURL(raw)
+	}
+
+	return uri.Host + uri.Path
+}
+
+// receivedMetricsTags returns the static tags for the received messages and bytes counters:
+// the connection tag is added (if enabled), so the load distribution across subscribers could be observed.
+// The role tag is added for subscribers connected to the same server (e.g., the split broadcasts and commands connections).
+func receivedMetricsTags(config *RedisConfig, role string) map[string]string {
+	if !config.MetricsConnectionTag {
+		return config.MetricsTags
+	}
+
+	tags := make(map[string]string, len(config.MetricsTags)+2)
+
+	for k, v := range config.MetricsTags {
+		tags[k] = v
+	}
+
+	tags[redisConnectionMetricsTag] = redisConnectionLabel(config.URL)
+
+	if role != "" {
+		tags[redisRoleMetricsTag] = role
+	}
+
+	return tags
+}
@@ -0,0 +1,116 @@
+package pubsub
+
+import (
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+const (
+	defaultCrashDumpMaxSize = 10 * 1024 * 1024
+	// The number of recent messages to include into a crash dump
+	crashDumpHistorySize = 100
+)
+
+// crashDumper keeps track of recent messages and writes them to a file
+// along with the message caused panic.
+// When the file exceeds the max size, it's rotated (only one backup file is kept).
+type crashDumper struct {
+	path    string
+	maxSize int64
+
+	mu      sync.Mutex
+	history []redisMessage
+	pos     int
+}
+
+func newCrashDumper(path string, maxSize int64) *crashDumper {
+	if maxSize <= 0 {
+		maxSize = defaultCrashDumpMaxSize
+	}
+
+	return &crashDumper{path: path, maxSize: maxSize, history: make([]redisMessage, 0, crashDumpHistorySize)}
+}
+
+// Record adds the message to the history
+func (d *crashDumper) Record(channel string, data []byte) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	msg := redisMessage{channel: channel, data: data}
+
+	if len(d.history) < crashDumpHistorySize {
+		d.history = append(d.history, msg)
+		return
+	}
+
+	d.history[d.pos] = msg
+	d.pos = (d.pos + 1) % crashDumpHistorySize
+}
+
+// Dump writes the message, the panic reason and the recent messages to the file
+func (d *crashDumper) Dump(channel string, data []byte, reason interface{}) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if err := d.rotate(); err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(d.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+
+	if err != nil {
+		return err
+	}
+
+	defer f.Close()
+
+	fmt.Fprintf(f, "=== %s panic: %v\n", time.Now().Format(time.RFC3339), reason)
+	fmt.Fprintf(f, "--- message (channel: %s)\n%s\n", channel, data)
+	fmt.Fprintf(f, "--- recent messages (%d)\n", len(d.history))
+
+	for i := 0; i < len(d.history); i++ {
+		msg := d.history[(d.pos+i)%len(d.history)]
+		fmt.Fprintf(f, "[%s] %s\n", msg.channel, msg.data)
+	}
+
+	return nil
+}
+
+func (d *crashDumper) rotate() error {
+	info, err := os.Stat(d.path)
+
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+
+		return err
+	}
+
+	if info.Size() < d.maxSize {
+		return nil
+	}
+
+	return os.Rename(d.path, d.path+".1")
+}
+
+// handleMessageWithCrashDump handles the message and writes a crash dump in case of panic
+func (s *RedisSubscriber) handleMessageWithCrashDump(channel string, data []byte, receivedAt time.Time) {
+	s.crashDumper.Record(channel, data)
+
+	defer func() {
+		if r := recover(); r != nil {
+			if err := s.crashDumper.Dump(channel, data, r); err != nil {
+				s.logger().Errorf("Failed to write crash dump: %v", err)
+			} else {
+				s.logger().Errorf("Panic while handling Redis message, crash dump is written to %s", s.crashDumper.path)
+			}
+
+			panic(r)
+		}
+	}()
+
+	s.handleMessageAt(channel, data, receivedAt)
+}
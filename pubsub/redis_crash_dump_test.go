@@ -0,0 +1,69 @@
+package pubsub
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/anycable/anycable-go/metrics"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type panickingHandler struct{}
+
+func (panickingHandler) HandlePubSub(msg []byte) {
+	if string(msg) == "boom" {
+		panic("boom!")
+	}
+}
+
+func TestRedisCrashDump(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "crash.log")
+
+	config := NewRedisConfig()
+	config.CrashDumpPath = path
+
+	subscriber := NewRedisSubscriber(panickingHandler{}, metrics.NoopMetrics{}, &config)
+
+	for i := 0; i < crashDumpHistorySize+10; i++ {
+		subscriber.handleMessageWithCrashDump("__anycable__", []byte(fmt.Sprintf("msg_%d", i)), time.Now())
+	}
+
+	assert.PanicsWithValue(t, "boom!", func() {
+		subscriber.handleMessageWithCrashDump("__anycable__", []byte("boom"), time.Now())
+	})
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+
+	dump := string(data)
+
+	assert.Contains(t, dump, "panic: boom!")
+	assert.Contains(t, dump, "--- message (channel: __anycable__)\nboom\n")
+	assert.Contains(t, dump, "[__anycable__] msg_109\n")
+	assert.NotContains(t, dump, "[__anycable__] msg_10\n")
+	assert.Equal(t, crashDumpHistorySize, strings.Count(dump, "[__anycable__]"))
+}
+
+func TestCrashDumperRotation(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "crash.log")
+
+	dumper := newCrashDumper(path, 10)
+
+	require.NoError(t, dumper.Dump("test", []byte("first"), "oops"))
+	require.NoError(t, dumper.Dump("test", []byte("second"), "oops"))
+
+	rotated, err := os.ReadFile(path + ".1")
+	require.NoError(t, err)
+
+	current, err := os.ReadFile(path)
+	require.NoError(t, err)
+
+	assert.Contains(t, string(rotated), "first")
+	assert.Contains(t, string(current), "second")
+	assert.NotContains(t, string(current), "first")
+}
@@ -0,0 +1,156 @@
+package pubsub
+
+import (
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/anycable/anycable-go/pubsub/logging"
+	"github.com/gomodule/redigo/redis"
+)
+
+const (
+	// The max number of dropped messages waiting to be written to the dead letter list.
+	// Messages are not written if the buffer is full.
+	deadLetterBufferSize = 100
+)
+
+// DeadLetterHandler is called for every message dropped by the subscriber
+type DeadLetterHandler func(channel string, msg []byte, reason string)
+
+// redisDeadLetterConfig contains the dead letter list settings
+type redisDeadLetterConfig struct {
+	// Redis list to push dropped messages to
+	Key string
+	// The max number of entries to keep in the list
+	MaxLen int
+}
+
+type deadLetter struct {
+	Channel string `json:"channel"`
+	Reason  string `json:"reason"`
+	Data    string `json:"data"`
+	At      int64  `json:"at"`
+}
+
+// redisDeadLetters writes dropped messages to a Redis list in the background
+// (via auxiliary connections, so the subscription connection is not affected)
+type redisDeadLetters struct {
+	config redisDeadLetterConfig
+	// conn returns a connection to write dead letters with (it's closed after every write)
+	conn   func() redis.Conn
+	memory *redisMemory
+	logger func() logging.Logger
+	ch     chan *deadLetter
+
+	wg       sync.WaitGroup
+	stopOnce sync.Once
+	stopCh   chan struct{}
+}
+
+func newRedisDeadLetters(config redisDeadLetterConfig, conn func() redis.Conn, logger func() logging.Logger) *redisDeadLetters {
+	return &redisDeadLetters{
+		config: config,
+		conn:   conn,
+		logger: logger,
+		ch:     make(chan *deadLetter, deadLetterBufferSize),
+		stopCh: make(chan struct{}),
+	}
+}
+
+// SetMemory sets the memory tracker to account buffered dead letters
+func (d *redisDeadLetters) SetMemory(memory *redisMemory) {
+	d.memory = memory
+}
+
+// Enabled returns true if the dead letter list is configured
+func (d *redisDeadLetters) Enabled() bool {
+	return d.config.Key != ""
+}
+
+// Start starts writing dead letters
+func (d *redisDeadLetters) Start() {
+	d.wg.Add(1)
+	go d.run()
+}
+
+// Stop stops writing dead letters (buffered ones not written yet are discarded)
+func (d *redisDeadLetters) Stop() {
+	d.stopOnce.Do(func() { close(d.stopCh) })
+	d.wg.Wait()
+}
+
+// Push puts the dropped message into the buffer without blocking (the message is lost if the buffer is full)
+func (d *redisDeadLetters) Push(channel string, msg []byte, reason string) {
+	if !d.Enabled() {
+		return
+	}
+
+	if d.memory.pressure() {
+		d.logger().Debug("Memory budget is approached, dead letter is lost")
+		return
+	}
+
+	letter := &deadLetter{Channel: channel, Reason: reason, Data: string(msg), At: time.Now().Unix()}
+
+	select {
+	case d.ch <- letter:
+		d.memory.reserve(messageSize(channel, msg))
+	default:
+		d.logger().Debug("Dead letter buffer is full, message is lost")
+	}
+}
+
+// run pushes dropped messages to the dead letter Redis list
+// keeping at most MaxLen recent entries
+func (d *redisDeadLetters) run() {
+	defer d.wg.Done()
+
+	for {
+		select {
+		case <-d.stopCh:
+			return
+		case letter := <-d.ch:
+			d.memory.release(len(letter.Channel) + len(letter.Data) + redisMemoryItemOverhead)
+
+			payload, err := json.Marshal(letter)
+
+			if err != nil {
+				continue
+			}
+
+			if err := d.write(payload); err != nil {
+				d.logger().Warnf("Failed to write dead letter to Redis: %v", err)
+			}
+		}
+	}
+}
+
+func (d *redisDeadLetters) write(payload []byte) error {
+	c := d.conn()
+	defer c.Close()
+
+	c.Send("LPUSH", d.config.Key, payload)              // nolint:errcheck
+	c.Send("LTRIM", d.config.Key, 0, d.config.MaxLen-1) // nolint:errcheck
+
+	_, err := c.Do("")
+	return err
+}
+
+// SetDeadLetterHandler sets a callback to be called for every dropped message
+func (s *RedisSubscriber) SetDeadLetterHandler(handler DeadLetterHandler) {
+	s.deadLetterHandler = handler
+}
+
+// drop is called every time a message is not delivered to the node for some reason
+func (s *RedisSubscriber) drop(channel string, msg []byte, reason string) {
+	s.metrics.CounterIncrement(metricsRedisDroppedMsg)
+	s.totals.drop(reason)
+	s.logger().Debugf("Dropped message from Redis channel %s (reason: %s)", channel, reason)
+
+	if s.deadLetterHandler != nil {
+		s.deadLetterHandler(channel, msg, reason)
+	}
+
+	s.deadLetters.Push(channel, msg, reason)
+}
@@ -0,0 +1,104 @@
+package pubsub
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/anycable/anycable-go/metrics"
+	"github.com/anycable/anycable-go/mocks"
+	"github.com/gomodule/redigo/redis"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRedisDeadLetters(t *testing.T) {
+	config := redisDeadLetterConfig{Key: "__anycable_dead__", MaxLen: 10}
+
+	t.Run("Buffers dropped messages", func(t *testing.T) {
+		letters := newRedisDeadLetters(config, nil, testRedisLogger())
+
+		for i := 0; i < deadLetterBufferSize+1; i++ {
+			letters.Push("__anycable__", []byte("hello"), "test")
+		}
+
+		assert.Len(t, letters.ch, deadLetterBufferSize)
+
+		letter := <-letters.ch
+
+		assert.Equal(t, "__anycable__", letter.Channel)
+		assert.Equal(t, "test", letter.Reason)
+		assert.Equal(t, "hello", letter.Data)
+	})
+
+	t.Run("Writes dropped messages to the list", func(t *testing.T) {
+		conns := make(chan *fakeRedisConn, 1)
+
+		letters := newRedisDeadLetters(config, func() redis.Conn {
+			conn := &fakeRedisConn{}
+			conns <- conn
+			return conn
+		}, testRedisLogger())
+
+		letters.Start()
+		letters.Push("__anycable__", []byte("hello"), "test")
+
+		var conn *fakeRedisConn
+
+		select {
+		case conn = <-conns:
+		case <-time.After(time.Second):
+			t.Fatal("Timed out waiting for the dead letter to be written")
+		}
+
+		letters.Stop()
+
+		assert.Equal(t, []string{"LPUSH", "LTRIM"}, conn.sent)
+	})
+
+	t.Run("Skips messages when memory budget is approached", func(t *testing.T) {
+		memory := newRedisMemory(100)
+		memory.reserve(100)
+
+		letters := newRedisDeadLetters(config, nil, testRedisLogger())
+		letters.SetMemory(memory)
+
+		letters.Push("__anycable__", []byte("hello"), "test")
+
+		assert.Empty(t, letters.ch)
+	})
+
+	t.Run("Disabled", func(t *testing.T) {
+		letters := newRedisDeadLetters(redisDeadLetterConfig{}, nil, testRedisLogger())
+
+		letters.Push("__anycable__", []byte("hello"), "test")
+
+		assert.False(t, letters.Enabled())
+		assert.Empty(t, letters.ch)
+	})
+}
+
+func TestDeadLetterJSON(t *testing.T) {
+	data, err := json.Marshal(&deadLetter{Channel: "__anycable__", Reason: "test", Data: "hello", At: 42})
+	require.NoError(t, err)
+
+	assert.JSONEq(t, `{"channel":"__anycable__","reason":"test","data":"hello","at":42}`, string(data))
+}
+
+func TestRedisDrop(t *testing.T) {
+	config := NewRedisConfig()
+	config.DeadLetterKey = "__anycable_dead__"
+
+	subscriber := NewRedisSubscriber(&mocks.Handler{}, metrics.NoopMetrics{}, &config)
+
+	var reasons []string
+
+	subscriber.SetDeadLetterHandler(func(channel string, msg []byte, reason string) {
+		reasons = append(reasons, reason)
+	})
+
+	subscriber.drop("__anycable__", []byte("hello"), "test")
+
+	assert.Equal(t, []string{"test"}, reasons)
+	assert.Len(t, subscriber.deadLetters.ch, 1)
+}
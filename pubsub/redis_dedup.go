@@ -0,0 +1,104 @@
+package pubsub
+
+import (
+	"container/list"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	defaultRedisDedupCacheSize = 10000
+	// Seconds
+	defaultRedisDedupTTL = 60
+
+	metricsRedisDedupHits = "redis_dedup_hits_total"
+)
+
+// dedupCache remembers the recently seen message IDs for the specified TTL.
+// The number of tracked IDs is bounded (the oldest ones are evicted first).
+type dedupCache struct {
+	limit  int
+	ttl    time.Duration
+	now    func() time.Time
+	ids    map[string]*list.Element
+	queue  *list.List
+	memory *redisMemory
+}
+
+type dedupEntry struct {
+	id        string
+	expiresAt time.Time
+}
+
+func newDedupCache(limit int, ttl time.Duration) *dedupCache {
+	if limit <= 0 {
+		limit = defaultRedisDedupCacheSize
+	}
+
+	if ttl <= 0 {
+		ttl = defaultRedisDedupTTL * time.Second
+	}
+
+	return &dedupCache{limit: limit, ttl: ttl, now: time.Now, ids: make(map[string]*list.Element), queue: list.New()}
+}
+
+// Seen returns true if the ID has been seen within the TTL window; otherwise, remembers it
+func (c *dedupCache) Seen(id string) bool {
+	now := c.now()
+
+	c.expire(now)
+
+	if _, ok := c.ids[id]; ok {
+		return true
+	}
+
+	c.ids[id] = c.queue.PushFront(&dedupEntry{id: id, expiresAt: now.Add(c.ttl)})
+	c.memory.reserve(len(id) + redisMemoryItemOverhead)
+
+	// Shrink the cache when the memory budget is approached
+	if c.queue.Len() > c.limit || (c.memory.pressure() && c.queue.Len() > 1) {
+		c.evict(c.queue.Back())
+	}
+
+	return false
+}
+
+// expire removes the expired entries (entries are ordered by expiration time, the oldest are at the back)
+func (c *dedupCache) expire(now time.Time) {
+	for el := c.queue.Back(); el != nil; el = c.queue.Back() {
+		if now.Before(el.Value.(*dedupEntry).expiresAt) {
+			return
+		}
+
+		c.evict(el)
+	}
+}
+
+func (c *dedupCache) evict(el *list.Element) {
+	id := el.Value.(*dedupEntry).id
+
+	c.queue.Remove(el)
+	delete(c.ids, id)
+	c.memory.release(len(id) + redisMemoryItemOverhead)
+}
+
+// isDuplicate returns true if the message carries an ID which has been already seen recently.
+// Messages without IDs are never considered duplicates.
+// Must be called from the receiving goroutine only.
+func (s *RedisSubscriber) isDuplicate(data []byte) bool {
+	id := extractPayloadField(data, s.dedupKey)
+
+	if id == "" {
+		return false
+	}
+
+	if !s.dedup.Seen(id) {
+		return false
+	}
+
+	s.metrics.CounterIncrement(metricsRedisDedupHits)
+	atomic.AddInt64(&s.totals.duplicates, 1)
+	s.logger().Debugf("Duplicate Redis message skipped: %s", id)
+
+	return true
+}
@@ -0,0 +1,98 @@
+package pubsub
+
+import (
+	"testing"
+	"time"
+
+	"github.com/anycable/anycable-go/metrics"
+	"github.com/anycable/anycable-go/mocks"
+	"github.com/gomodule/redigo/redis"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDedupCache(t *testing.T) {
+	now := time.Now()
+
+	cache := newDedupCache(2, time.Minute)
+	cache.now = func() time.Time { return now }
+
+	assert.False(t, cache.Seen("a"))
+	assert.True(t, cache.Seen("a"))
+	assert.False(t, cache.Seen("b"))
+	assert.False(t, cache.Seen("c"))
+
+	// "a" is evicted as the oldest one
+	assert.False(t, cache.Seen("a"))
+	assert.True(t, cache.Seen("c"))
+
+	now = now.Add(2 * time.Minute)
+
+	// Everything is expired
+	assert.False(t, cache.Seen("c"))
+	assert.Equal(t, 1, cache.queue.Len())
+}
+
+func TestDedupCacheMemoryBudget(t *testing.T) {
+	memory := newRedisMemory(int64(3 * (1 + redisMemoryItemOverhead)))
+
+	cache := newDedupCache(10, time.Minute)
+	cache.memory = memory
+
+	assert.False(t, cache.Seen("a"))
+	assert.False(t, cache.Seen("b"))
+	assert.False(t, cache.Seen("c"))
+
+	// The cache is shrunk under memory pressure
+	assert.Equal(t, 2, cache.queue.Len())
+	assert.False(t, cache.Seen("a"))
+	assert.Equal(t, int64(2*(1+redisMemoryItemOverhead)), memory.Stats().Used)
+}
+
+func TestRedisReceiveDedup(t *testing.T) {
+	config := NewRedisConfig()
+	config.DedupKey = "id"
+
+	m := metrics.NewMetrics(nil, 0)
+	handler := &mocks.Handler{}
+	subscriber := NewRedisSubscriber(handler, m, &config)
+
+	payload := `{"stream":"chat","data":"hi","id":"42"}`
+	handler.On("HandlePubSub", []byte(payload))
+
+	conn := &fakeRedisConn{reply: redisMessageReply("__anycable__", payload), limit: 3}
+	done := make(chan error, 1)
+
+	subscriber.receive(redis.PubSubConn{Conn: conn}, done)
+	<-done
+
+	handler.AssertNumberOfCalls(t, "HandlePubSub", 1)
+	assert.Equal(t, uint64(2), m.Counter(metricsRedisDedupHits).Value())
+
+	// Messages without IDs are always delivered
+	plain := `{"stream":"chat","data":"hi"}`
+	handler.On("HandlePubSub", []byte(plain))
+
+	conn = &fakeRedisConn{reply: redisMessageReply("__anycable__", plain), limit: 2}
+	subscriber.receive(redis.PubSubConn{Conn: conn}, done)
+
+	handler.AssertNumberOfCalls(t, "HandlePubSub", 3)
+}
+
+func TestRedisInjectBroadcast(t *testing.T) {
+	config := NewRedisConfig()
+	config.DedupKey = "id"
+
+	m := metrics.NewMetrics(nil, 0)
+	handler := &mocks.Handler{}
+	subscriber := NewRedisSubscriber(handler, m, &config)
+
+	payload := `{"stream":"chat","data":"hi","id":"42"}`
+	handler.On("HandlePubSub", []byte(payload))
+
+	subscriber.InjectBroadcast("__anycable__", []byte(payload))
+	subscriber.InjectBroadcast("__anycable__", []byte(payload))
+
+	handler.AssertNumberOfCalls(t, "HandlePubSub", 1)
+	assert.Equal(t, uint64(2), m.Counter(metricsRedisReceivedMsg).Value())
+	assert.Equal(t, uint64(1), m.Counter(metricsRedisDedupHits).Value())
+}
@@ -0,0 +1,68 @@
+package pubsub
+
+import "errors"
+
+const (
+	defaultRedisDispatchRetryDelay = 100
+
+	metricsRedisDispatchRetries = "redis_dispatch_retries_total"
+	metricsRedisDispatchFailed  = "redis_dispatch_failed_total"
+)
+
+// RetryableError wraps a dispatch error which is likely to clear soon (e.g., the node is busy);
+// CheckedHandler implementations return it to request re-dispatching the message
+type RetryableError struct {
+	Err error
+}
+
+func (e *RetryableError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *RetryableError) Unwrap() error {
+	return e.Err
+}
+
+// IsRetryable returns true if the dispatch error is (or wraps) RetryableError
+func IsRetryable(err error) bool {
+	var retryable *RetryableError
+
+	return errors.As(err, &retryable)
+}
+
+// deliverChecked passes the broadcast to the handler reporting failures and re-attempts retryable ones
+// (up to the configured number of times); the message is dropped if the handler fails eventually.
+// Returns true if the message has been handled.
+func (s *RedisSubscriber) deliverChecked(handler CheckedHandler, channel string, data []byte) bool {
+	err := handler.HandlePubSubChecked(data)
+
+	for attempt := 1; err != nil && IsRetryable(err) && attempt <= s.dispatchRetries; attempt++ {
+		s.metrics.CounterIncrement(metricsRedisDispatchRetries)
+
+		if !s.waitDispatchRetry() {
+			break
+		}
+
+		err = handler.HandlePubSubChecked(data)
+	}
+
+	if err == nil {
+		return true
+	}
+
+	s.metrics.CounterIncrement(metricsRedisDispatchFailed)
+	s.logger().Debugf("Failed to handle message from Redis channel %s: %v", channel, err)
+	s.drop(channel, data, "dispatch_failed")
+
+	return false
+}
+
+// waitDispatchRetry waits for the delay between dispatch attempts; returns false if the subscriber is shutting down
+func (s *RedisSubscriber) waitDispatchRetry() bool {
+	select {
+	case <-s.shutdownCh:
+		return false
+	case <-s.clock.after(s.dispatchRetryDelay):
+		return true
+	}
+}
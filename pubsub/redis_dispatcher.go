@@ -0,0 +1,362 @@
+package pubsub
+
+import (
+	"bytes"
+	"fmt"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// The max number of dispatch workers used by default
+	maxDefaultDispatchWorkers = 4
+	// The size of the per-worker messages buffer
+	dispatchBufferSize = 256
+	// How many leading payload bytes to scan for the broadcast stream to pick the worker by
+	dispatchStreamScanLimit = 512
+
+	fnvOffset32 = 2166136261
+	fnvPrime32  = 16777619
+
+	// Wait for the queue to have free space (backpressure)
+	dispatchOverflowBlock = "block"
+	// Drop the incoming message if the queue is full
+	dispatchOverflowDropNew = "drop_new"
+	// Evict the oldest enqueued message to admit the incoming one
+	dispatchOverflowDropOldest = "drop_oldest"
+
+	metricsRedisDispatchBlocked       = "redis_dispatch_blocked_total"
+	metricsRedisDispatchDroppedNew    = "redis_dispatch_dropped_new_total"
+	metricsRedisDispatchDroppedOldest = "redis_dispatch_dropped_oldest_total"
+)
+
+// Broadcast payload field to pick the worker by
+var dispatchStreamKey = []byte(`"stream"`)
+
+// RedisDispatchStats contains messages dispatching statistics
+type RedisDispatchStats struct {
+	// Queue overflow policy
+	Policy string
+	// The total number of times dispatching was blocked due to a full queue (block policy)
+	Blocked int64
+	// The total number of incoming messages dropped due to a full queue (drop_new policy)
+	DroppedNew int64
+	// The total number of enqueued messages evicted due to a full queue (drop_oldest policy)
+	DroppedOldest int64
+}
+
+func validateDispatchOverflowPolicy(policy string) error {
+	switch policy {
+	case dispatchOverflowBlock, dispatchOverflowDropNew, dispatchOverflowDropOldest:
+		return nil
+	default:
+		return fmt.Errorf("unknown dispatch overflow policy: %s", policy)
+	}
+}
+
+type redisMessage struct {
+	channel    string
+	data       []byte
+	receivedAt time.Time
+}
+
+type redisMessageHandler func(channel string, data []byte, receivedAt time.Time)
+
+// redisDispatcher passes messages to the handler using a pool of workers.
+// Broadcasts for the same stream (and other messages from the same channel) are always processed by the same worker,
+// so the ordering is preserved within a stream (but not across streams).
+// All the broadcasts usually come from the same Redis channel, so workers are picked by the broadcast stream.
+type redisDispatcher struct {
+	queues     []chan redisMessage
+	handler    redisMessageHandler
+	policy     string
+	onOverflow func(policy string, msg redisMessage)
+	memory     *redisMemory
+	pressure   func() bool
+	wg         sync.WaitGroup
+	stopOnce   sync.Once
+	// Queues are only closed under the write lock, so Dispatch (holding the read lock) never sends to a closed queue
+	mu     sync.RWMutex
+	stopCh chan struct{}
+
+	blocked       int64
+	droppedNew    int64
+	droppedOldest int64
+}
+
+func defaultDispatchWorkers() int {
+	workers := runtime.NumCPU()
+
+	if workers > maxDefaultDispatchWorkers {
+		return maxDefaultDispatchWorkers
+	}
+
+	return workers
+}
+
+func newRedisDispatcher(workers int, handler redisMessageHandler) *redisDispatcher {
+	if workers <= 0 {
+		workers = defaultDispatchWorkers()
+	}
+
+	d := &redisDispatcher{
+		queues:  make([]chan redisMessage, workers),
+		handler: handler,
+		policy:  dispatchOverflowBlock,
+		stopCh:  make(chan struct{}),
+	}
+
+	for i := range d.queues {
+		d.queues[i] = make(chan redisMessage, dispatchBufferSize)
+	}
+
+	return d
+}
+
+// Start runs workers
+func (d *redisDispatcher) Start() {
+	for _, queue := range d.queues {
+		d.wg.Add(1)
+		go d.work(queue)
+	}
+}
+
+// SetOverflowPolicy sets the queue overflow policy and a callback to be called
+// every time the queue is full (with the dropped message for drop_* policies).
+// Must be called before Start.
+func (d *redisDispatcher) SetOverflowPolicy(policy string, onOverflow func(policy string, msg redisMessage)) {
+	d.policy = policy
+	d.onOverflow = onOverflow
+}
+
+// SetMemory sets the memory budget to account enqueued messages in.
+// Must be called before Start.
+func (d *redisDispatcher) SetMemory(memory *redisMemory) {
+	d.memory = memory
+}
+
+// SetPressure sets the function reporting whether the handler is under pressure,
+// so the overflow policy is applied before the queue is full (and reading is slowed down for the block policy).
+// Must be called before Start.
+func (d *redisDispatcher) SetPressure(pressure func() bool) {
+	d.pressure = pressure
+}
+
+// Dispatch enqueues the message to the channel's worker queue.
+// When the queue is full (or the memory budget is approached), the behaviour depends on the overflow policy.
+// Messages dispatched after Stop are discarded.
+func (d *redisDispatcher) Dispatch(channel string, data []byte) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	if d.stopped() {
+		return
+	}
+
+	queue := d.queues[d.index(channel, data)]
+	msg := redisMessage{channel: channel, data: data, receivedAt: time.Now()}
+
+	underPressure := d.pressure != nil && d.pressure()
+
+	if d.memory.pressure() || underPressure {
+		switch d.policy {
+		case dispatchOverflowDropNew:
+			atomic.AddInt64(&d.droppedNew, 1)
+			d.overflow(msg)
+			return
+		case dispatchOverflowDropOldest:
+			d.evictOldest(queue)
+		default:
+			if underPressure {
+				time.Sleep(redisNodePressureDelay)
+			}
+		}
+	}
+
+	d.memory.reserve(messageSize(channel, data))
+
+	select {
+	case queue <- msg:
+		return
+	case <-d.stopCh:
+		d.memory.release(messageSize(channel, data))
+		return
+	default:
+	}
+
+	switch d.policy {
+	case dispatchOverflowDropNew:
+		d.memory.release(messageSize(channel, data))
+		atomic.AddInt64(&d.droppedNew, 1)
+		d.overflow(msg)
+	case dispatchOverflowDropOldest:
+		for {
+			select {
+			case queue <- msg:
+				return
+			default:
+			}
+
+			d.evictOldest(queue)
+		}
+	default:
+		atomic.AddInt64(&d.blocked, 1)
+		d.overflow(msg)
+
+		select {
+		case queue <- msg:
+		case <-d.stopCh:
+			d.memory.release(messageSize(channel, data))
+		}
+	}
+}
+
+// Stats returns dispatching statistics
+func (d *redisDispatcher) Stats() RedisDispatchStats {
+	return RedisDispatchStats{
+		Policy:        d.policy,
+		Blocked:       atomic.LoadInt64(&d.blocked),
+		DroppedNew:    atomic.LoadInt64(&d.droppedNew),
+		DroppedOldest: atomic.LoadInt64(&d.droppedOldest),
+	}
+}
+
+// Stop waits for all the enqueued messages to be processed and stops workers (could be called multiple times).
+// Pending Dispatch calls blocked on full queues are released (and their messages are discarded).
+func (d *redisDispatcher) Stop() {
+	d.stopOnce.Do(func() {
+		close(d.stopCh)
+
+		d.mu.Lock()
+		defer d.mu.Unlock()
+
+		for _, queue := range d.queues {
+			close(queue)
+		}
+	})
+
+	d.wg.Wait()
+}
+
+func (d *redisDispatcher) stopped() bool {
+	select {
+	case <-d.stopCh:
+		return true
+	default:
+		return false
+	}
+}
+
+// Size returns the number of workers
+func (d *redisDispatcher) Size() int {
+	return len(d.queues)
+}
+
+// index returns the worker index for the message: by the broadcast stream if any or by the channel otherwise
+// (e.g., for internal commands or non-JSON payloads)
+func (d *redisDispatcher) index(channel string, data []byte) int {
+	if len(d.queues) == 1 {
+		return 0
+	}
+
+	var hash uint32
+
+	if key := scanDispatchStream(data); key != nil {
+		hash = fnv32a(key)
+	} else {
+		hash = fnv32a([]byte(channel))
+	}
+
+	return int(hash % uint32(len(d.queues)))
+}
+
+// scanDispatchStream returns the raw (still escaped) value of the top-level stream field of the payload
+// or nil if it's not found within the first dispatchStreamScanLimit bytes.
+// It's called for every message, so it doesn't parse the payload: broadcasters put the stream first,
+// and string values can't contain unescaped quotes, so the first "stream" followed by a colon is the key.
+func scanDispatchStream(data []byte) []byte {
+	if len(data) > dispatchStreamScanLimit {
+		data = data[:dispatchStreamScanLimit]
+	}
+
+	for {
+		i := bytes.Index(data, dispatchStreamKey)
+
+		if i < 0 {
+			return nil
+		}
+
+		data = skipJSONSpaces(data[i+len(dispatchStreamKey):])
+
+		if len(data) == 0 || data[0] != ':' {
+			continue
+		}
+
+		data = skipJSONSpaces(data[1:])
+
+		if len(data) == 0 || data[0] != '"' {
+			return nil
+		}
+
+		for j := 1; j < len(data); j++ {
+			switch data[j] {
+			case '\\':
+				j++
+			case '"':
+				return data[1:j]
+			}
+		}
+
+		return nil
+	}
+}
+
+func skipJSONSpaces(data []byte) []byte {
+	for len(data) > 0 && (data[0] == ' ' || data[0] == '\t' || data[0] == '\n' || data[0] == '\r') {
+		data = data[1:]
+	}
+
+	return data
+}
+
+// fnv32a is an allocation-free version of hash/fnv's New32a
+func fnv32a(data []byte) uint32 {
+	hash := uint32(fnvOffset32)
+
+	for _, c := range data {
+		hash ^= uint32(c)
+		hash *= fnvPrime32
+	}
+
+	return hash
+}
+
+func (d *redisDispatcher) evictOldest(queue chan redisMessage) {
+	select {
+	case old := <-queue:
+		d.memory.release(messageSize(old.channel, old.data))
+		atomic.AddInt64(&d.droppedOldest, 1)
+		d.overflow(old)
+	default:
+	}
+}
+
+func (d *redisDispatcher) overflow(msg redisMessage) {
+	if d.onOverflow != nil {
+		d.onOverflow(d.policy, msg)
+	}
+}
+
+func (d *redisDispatcher) work(queue chan redisMessage) {
+	defer d.wg.Done()
+
+	labels := newRedisChannelLabels("dispatch")
+
+	for msg := range queue {
+		labels.apply(msg.channel)
+		d.handler(msg.channel, msg.data, msg.receivedAt)
+		d.memory.release(messageSize(msg.channel, msg.data))
+	}
+}
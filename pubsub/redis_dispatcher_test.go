@@ -0,0 +1,373 @@
+package pubsub
+
+import (
+	"fmt"
+	"runtime/pprof"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRedisDispatcher(t *testing.T) {
+	var mu sync.Mutex
+
+	received := make(map[string][]string)
+
+	dispatcher := newRedisDispatcher(4, func(channel string, data []byte, _ time.Time) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		received[channel] = append(received[channel], string(data))
+	})
+
+	assert.Equal(t, 4, dispatcher.Size())
+
+	dispatcher.Start()
+
+	for i := 0; i < 100; i++ {
+		dispatcher.Dispatch(fmt.Sprintf("channel_%d", i%3), []byte(fmt.Sprintf("%d", i)))
+	}
+
+	dispatcher.Stop()
+
+	assert.Len(t, received, 3)
+
+	for i := 0; i < 3; i++ {
+		expected := []string{}
+
+		for j := i; j < 100; j += 3 {
+			expected = append(expected, fmt.Sprintf("%d", j))
+		}
+
+		assert.Equal(t, expected, received[fmt.Sprintf("channel_%d", i)])
+	}
+}
+
+func TestRedisDispatcherStreams(t *testing.T) {
+	var mu sync.Mutex
+
+	received := make(map[string][]string)
+
+	dispatcher := newRedisDispatcher(4, func(channel string, data []byte, _ time.Time) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		stream := extractPayloadField(data, "stream")
+		received[stream] = append(received[stream], extractPayloadField(data, "data"))
+	})
+
+	workers := make(map[int]bool)
+
+	for i := 0; i < 8; i++ {
+		workers[dispatcher.index("__anycable__", []byte(fmt.Sprintf(`{"stream":"chat_%d"}`, i)))] = true
+	}
+
+	// Broadcasts from the same channel are spread across workers
+	assert.Greater(t, len(workers), 1)
+
+	dispatcher.Start()
+
+	for i := 0; i < 100; i++ {
+		dispatcher.Dispatch("__anycable__", []byte(fmt.Sprintf(`{"stream":"chat_%d","data":"%d"}`, i%8, i)))
+	}
+
+	dispatcher.Stop()
+
+	require.Len(t, received, 8)
+
+	for i := 0; i < 8; i++ {
+		expected := []string{}
+
+		for j := i; j < 100; j += 8 {
+			expected = append(expected, fmt.Sprintf("%d", j))
+		}
+
+		assert.Equal(t, expected, received[fmt.Sprintf("chat_%d", i)])
+	}
+}
+
+func TestRedisDispatcherOverflow(t *testing.T) {
+	fill := func(policy string) (*redisDispatcher, []string) {
+		var dropped []string
+
+		dispatcher := newRedisDispatcher(1, func(string, []byte, time.Time) {})
+		dispatcher.SetOverflowPolicy(policy, func(_ string, msg redisMessage) {
+			dropped = append(dropped, string(msg.data))
+		})
+
+		// Workers are not started, so the queue is not drained
+		for i := 0; i < dispatchBufferSize+2; i++ {
+			dispatcher.Dispatch("channel", []byte(fmt.Sprintf("%d", i)))
+		}
+
+		return dispatcher, dropped
+	}
+
+	t.Run("drop_new", func(t *testing.T) {
+		dispatcher, dropped := fill(dispatchOverflowDropNew)
+
+		assert.Equal(t, []string{"256", "257"}, dropped)
+		assert.Equal(t, int64(2), dispatcher.Stats().DroppedNew)
+		assert.Equal(t, "0", string((<-dispatcher.queues[0]).data))
+	})
+
+	t.Run("drop_oldest", func(t *testing.T) {
+		dispatcher, dropped := fill(dispatchOverflowDropOldest)
+
+		assert.Equal(t, []string{"0", "1"}, dropped)
+		assert.Equal(t, int64(2), dispatcher.Stats().DroppedOldest)
+		assert.Equal(t, "2", string((<-dispatcher.queues[0]).data))
+	})
+
+	t.Run("block", func(t *testing.T) {
+		dispatcher := newRedisDispatcher(1, func(string, []byte, time.Time) {})
+
+		for i := 0; i < dispatchBufferSize; i++ {
+			dispatcher.Dispatch("channel", []byte("msg"))
+		}
+
+		dispatched := make(chan struct{})
+
+		go func() {
+			dispatcher.Dispatch("channel", []byte("msg"))
+			close(dispatched)
+		}()
+
+		select {
+		case <-dispatched:
+			t.Fatal("Dispatch must block when the queue is full")
+		case <-time.After(100 * time.Millisecond):
+		}
+
+		dispatcher.Start()
+		<-dispatched
+		dispatcher.Stop()
+
+		assert.Equal(t, int64(1), dispatcher.Stats().Blocked)
+		assert.Equal(t, dispatchOverflowBlock, dispatcher.Stats().Policy)
+	})
+}
+
+func TestValidateDispatchOverflowPolicy(t *testing.T) {
+	assert.NoError(t, validateDispatchOverflowPolicy("block"))
+	assert.NoError(t, validateDispatchOverflowPolicy("drop_new"))
+	assert.NoError(t, validateDispatchOverflowPolicy("drop_oldest"))
+	assert.Error(t, validateDispatchOverflowPolicy("drop_all"))
+}
+
+func TestDefaultDispatchWorkers(t *testing.T) {
+	dispatcher := newRedisDispatcher(0, func(string, []byte, time.Time) {})
+
+	assert.Equal(t, defaultDispatchWorkers(), dispatcher.Size())
+	assert.LessOrEqual(t, dispatcher.Size(), maxDefaultDispatchWorkers)
+}
+
+func TestRedisDispatcherStop(t *testing.T) {
+	t.Run("Dispatch after stop is discarded", func(t *testing.T) {
+		dispatcher := newRedisDispatcher(1, func(string, []byte, time.Time) {
+			t.Fatal("Message must not be handled")
+		})
+
+		dispatcher.Start()
+		dispatcher.Stop()
+
+		assert.NotPanics(t, func() { dispatcher.Dispatch("channel", []byte("late")) })
+	})
+
+	t.Run("Stop releases blocked dispatch", func(t *testing.T) {
+		release := make(chan struct{})
+
+		var handled int32
+
+		dispatcher := newRedisDispatcher(1, func(string, []byte, time.Time) {
+			<-release
+			atomic.AddInt32(&handled, 1)
+		})
+
+		dispatcher.Start()
+
+		// The first message is taken by the worker, the rest fill the queue
+		for i := 0; i <= dispatchBufferSize; i++ {
+			dispatcher.Dispatch("channel", []byte("msg"))
+		}
+
+		blocked := make(chan struct{})
+
+		go func() {
+			dispatcher.Dispatch("channel", []byte("blocked"))
+			close(blocked)
+		}()
+
+		select {
+		case <-blocked:
+			t.Fatal("Dispatch must block when the queue is full")
+		case <-time.After(50 * time.Millisecond):
+		}
+
+		stopped := make(chan struct{})
+
+		go func() {
+			dispatcher.Stop()
+			close(stopped)
+		}()
+
+		select {
+		case <-blocked:
+		case <-time.After(time.Second):
+			t.Fatal("Blocked dispatch hasn't been released by Stop")
+		}
+
+		close(release)
+		<-stopped
+
+		assert.Equal(t, int32(dispatchBufferSize+1), atomic.LoadInt32(&handled))
+	})
+}
+
+func TestScanDispatchStream(t *testing.T) {
+	for payload, expected := range map[string]string{
+		`{"stream":"chat_1","data":"hi"}`:            "chat_1",
+		`{ "stream" : "chat_1" }`:                    "chat_1",
+		`{"data":"stream","stream":"chat_1"}`:        "chat_1",
+		`{"data":"{\"stream\":\"x\"}","stream":"y"}`: "y",
+		`{"stream":"chat_\"1\""}`:                    `chat_\"1\"`,
+		`{"stream":1}`:                               "",
+		`{"stream":"chat_1`:                          "",
+		`{"data":"hi"}`:                              "",
+		`hello`:                                      "",
+	} {
+		assert.Equal(t, expected, string(scanDispatchStream([]byte(payload))), payload)
+	}
+
+	padded := fmt.Sprintf(`{"data":"%s","stream":"chat_1"}`, strings.Repeat("x", dispatchStreamScanLimit))
+	assert.Nil(t, scanDispatchStream([]byte(padded)))
+}
+
+func BenchmarkRedisDispatcherIndex(b *testing.B) {
+	dispatcher := newRedisDispatcher(4, func(string, []byte, time.Time) {})
+	payload := []byte(`{"stream":"chat_1","data":"{\"message\":\"hello\"}"}`)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		dispatcher.index("__anycable__", payload)
+	}
+}
+
+func BenchmarkRedisDispatcher(b *testing.B) {
+	// All the broadcasts come from the same Redis channel (as in the default setup)
+	payloads := make([][]byte, 8)
+
+	for i := range payloads {
+		payloads[i] = []byte(fmt.Sprintf(`{"stream":"chat_%d","data":"{\"message\":\"hello\"}"}`, i))
+	}
+
+	for _, workers := range []int{1, 2, 4, 8} {
+		b.Run(fmt.Sprintf("workers=%d", workers), func(b *testing.B) {
+			dispatcher := newRedisDispatcher(workers, func(channel string, data []byte, _ time.Time) {
+				time.Sleep(time.Microsecond)
+			})
+
+			dispatcher.Start()
+
+			b.ResetTimer()
+
+			for i := 0; i < b.N; i++ {
+				dispatcher.Dispatch("__anycable__", payloads[i%len(payloads)])
+			}
+
+			dispatcher.Stop()
+		})
+	}
+}
+
+func TestRedisDispatcherMemoryBudget(t *testing.T) {
+	memory := newRedisMemory(int64(messageSize("channel", []byte("msg")) * 2))
+
+	var dropped []string
+
+	dispatcher := newRedisDispatcher(1, func(string, []byte, time.Time) {})
+	dispatcher.SetOverflowPolicy(dispatchOverflowDropNew, func(_ string, msg redisMessage) {
+		dropped = append(dropped, string(msg.data))
+	})
+	dispatcher.SetMemory(memory)
+
+	dispatcher.Dispatch("channel", []byte("msg"))
+	dispatcher.Dispatch("channel", []byte("msg"))
+	dispatcher.Dispatch("channel", []byte("new"))
+
+	assert.Equal(t, []string{"new"}, dropped)
+	assert.Equal(t, int64(messageSize("channel", []byte("msg"))*2), memory.Stats().Used)
+
+	dispatcher.Start()
+	dispatcher.Stop()
+
+	assert.Equal(t, int64(0), memory.Stats().Used)
+}
+
+func TestRedisDispatcherPressure(t *testing.T) {
+	underPressure := true
+
+	var dropped []string
+
+	dispatcher := newRedisDispatcher(1, func(string, []byte, time.Time) {})
+	dispatcher.SetOverflowPolicy(dispatchOverflowDropNew, func(_ string, msg redisMessage) {
+		dropped = append(dropped, string(msg.data))
+	})
+	dispatcher.SetPressure(func() bool { return underPressure })
+
+	dispatcher.Dispatch("channel", []byte("first"))
+
+	underPressure = false
+
+	dispatcher.Dispatch("channel", []byte("second"))
+
+	assert.Equal(t, []string{"first"}, dropped)
+	assert.Len(t, dispatcher.queues[0], 1)
+
+	t.Run("Slows down dispatching with the block policy", func(t *testing.T) {
+		dispatcher := newRedisDispatcher(1, func(string, []byte, time.Time) {})
+		dispatcher.SetPressure(func() bool { return true })
+
+		start := time.Now()
+
+		dispatcher.Dispatch("channel", []byte("msg"))
+
+		assert.GreaterOrEqual(t, time.Since(start), redisNodePressureDelay)
+		assert.Len(t, dispatcher.queues[0], 1)
+		assert.Equal(t, int64(0), dispatcher.Stats().Blocked)
+	})
+}
+
+func TestRedisChannelLabels(t *testing.T) {
+	labels := newRedisChannelLabels("dispatch")
+
+	labels.apply("chat")
+	labels.apply("chat")
+	labels.apply("news")
+
+	require.Len(t, labels.contexts, 2)
+
+	ctx := labels.contexts["chat"]
+
+	backend, _ := pprof.Label(ctx, redisProfilingBackendLabel)
+	routine, _ := pprof.Label(ctx, redisProfilingRoutineLabel)
+	channel, _ := pprof.Label(ctx, redisProfilingChannelLabel)
+
+	assert.Equal(t, "redis", backend)
+	assert.Equal(t, "dispatch", routine)
+	assert.Equal(t, "chat", channel)
+
+	// The cache is reset when the limit is reached
+	for i := 0; i < redisProfilingChannelsLimit; i++ {
+		labels.apply(fmt.Sprintf("channel_%d", i))
+	}
+
+	assert.LessOrEqual(t, len(labels.contexts), redisProfilingChannelsLimit)
+}
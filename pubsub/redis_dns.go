@@ -0,0 +1,80 @@
+package pubsub
+
+import (
+	"errors"
+	"net"
+	"time"
+)
+
+const (
+	// How long to wait before retrying after a temporary DNS failure
+	redisDNSRetryInterval = time.Second
+	// The max number of temporary DNS failures in a row retried without counting reconnect attempts
+	redisMaxDNSRetries = 10
+)
+
+// redisDNSFailure returns the DNS error if the error is caused by a host resolution failure
+func redisDNSFailure(err error) (*net.DNSError, bool) {
+	var dnsErr *net.DNSError
+
+	if errors.As(err, &dnsErr) {
+		return dnsErr, true
+	}
+
+	return nil, false
+}
+
+// isTemporaryDNSFailure returns true if the error is caused by a DNS failure which is likely to clear soon
+// (e.g., a DNS server timeout), as opposed to a permanent one (e.g., no such host)
+func isTemporaryDNSFailure(err error) bool {
+	dnsErr, ok := redisDNSFailure(err)
+
+	return ok && !dnsErr.IsNotFound && (dnsErr.IsTemporary || dnsErr.IsTimeout)
+}
+
+// logConnectError logs the connection failure; DNS failures are reported distinctly from Redis being unavailable
+func (s *RedisSubscriber) logConnectError(err error, cold bool) {
+	logf := s.logger().Warnf
+
+	if _, lost := redisConnectionLoss(err); cold || lost {
+		logf = s.logger().Debugf
+	}
+
+	if dnsErr, ok := redisDNSFailure(err); ok {
+		kind := "permanent"
+
+		if isTemporaryDNSFailure(err) {
+			kind = "temporary"
+		}
+
+		logf("Failed to resolve Redis host %s (%s DNS failure): %v", dnsErr.Name, kind, err)
+		return
+	}
+
+	logf("Redis connection failed: %v", err)
+}
+
+// retryDNSFailure waits for a short interval and returns true if the error is a temporary DNS failure,
+// so the connection is retried sooner and without counting a reconnect attempt
+// (but no more than the max number of DNS retries in a row)
+func (s *RedisSubscriber) retryDNSFailure(err error) bool {
+	if !isTemporaryDNSFailure(err) {
+		s.dnsRetries = 0
+		return false
+	}
+
+	if s.dnsRetries >= redisMaxDNSRetries {
+		return false
+	}
+
+	s.dnsRetries++
+
+	s.logger().Infof("Retrying Redis connection after temporary DNS failure in %s (%d of %d)", redisDNSRetryInterval, s.dnsRetries, redisMaxDNSRetries)
+
+	select {
+	case <-s.shutdownCh:
+		return false
+	case <-s.clock.after(redisDNSRetryInterval):
+		return true
+	}
+}
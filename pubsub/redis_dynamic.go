@@ -0,0 +1,217 @@
+package pubsub
+
+import (
+	"bufio"
+	"bytes"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+)
+
+const (
+	// How often to check the channels file for changes
+	redisChannelsFilePollInterval = 2 * time.Second
+)
+
+// Subscribe adds channels to subscribe to in addition to the configured ones.
+// Could be called while the subscriber is running; the subscriptions are updated in the background.
+// In sharded mode, channels must belong to the same hash slot as the broadcasts channel (none are added otherwise).
+func (s *RedisSubscriber) Subscribe(channels ...string) error {
+	if err := s.checkSlot(channels...); err != nil {
+		return err
+	}
+
+	s.dynamicMu.Lock()
+
+	for _, channel := range channels {
+		s.dynamicChannels[channel] = struct{}{}
+	}
+
+	s.dynamicMu.Unlock()
+
+	s.notifyChannelsChanged()
+
+	return nil
+}
+
+// Unsubscribe removes channels previously added via Subscribe
+func (s *RedisSubscriber) Unsubscribe(channels ...string) {
+	s.dynamicMu.Lock()
+
+	for _, channel := range channels {
+		delete(s.dynamicChannels, channel)
+	}
+
+	s.dynamicMu.Unlock()
+
+	s.notifyChannelsChanged()
+}
+
+func (s *RedisSubscriber) notifyChannelsChanged() {
+	select {
+	case s.channelsChangedCh <- struct{}{}:
+	default:
+	}
+}
+
+func (s *RedisSubscriber) dynamicChannelsList() []string {
+	s.dynamicMu.Lock()
+	defer s.dynamicMu.Unlock()
+
+	channels := make([]string, 0, len(s.dynamicChannels))
+
+	for channel := range s.dynamicChannels {
+		channels = append(channels, channel)
+	}
+
+	sort.Strings(channels)
+
+	return channels
+}
+
+// syncSubscriptions subscribes to the added channels and unsubscribes from the removed ones.
+// Must be called from the listening goroutine (the one sending commands).
+func (s *RedisSubscriber) syncSubscriptions(psc redis.PubSubConn) error {
+	desired := s.channels()
+	current := s.subscriptions.snapshot()
+
+	var added []string
+
+	for _, channel := range desired {
+		if _, ok := current[channel]; !ok {
+			added = append(added, channel)
+		}
+
+		delete(current, channel)
+	}
+
+	removed := make([]string, 0, len(current))
+
+	for channel := range current {
+		removed = append(removed, channel)
+	}
+
+	sort.Strings(removed)
+
+	if len(added) > 0 {
+		s.logger().Infof("Subscribing to Redis channels: %v", added)
+
+		if err := s.subscriptions.subscribe(psc, added); err != nil {
+			return err
+		}
+	}
+
+	if len(removed) > 0 {
+		s.logger().Infof("Unsubscribing from Redis channels: %v", removed)
+
+		if err := s.subscriptions.unsubscribe(psc, removed); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// parseChannelsFile returns the list of channels from the file contents
+// (one channel per line; empty lines and lines starting with # are ignored)
+func parseChannelsFile(data []byte) []string {
+	var channels []string
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		channels = append(channels, line)
+	}
+
+	return channels
+}
+
+// loadChannelsFile reads the channels file and applies the difference with the previously loaded channels
+// (channels which can't be subscribed to, e.g., from other hash slots in sharded mode, are skipped)
+func (s *RedisSubscriber) loadChannelsFile(data []byte) {
+	channels := parseChannelsFile(data)
+	loaded := make(map[string]struct{}, len(channels))
+
+	var added []string
+
+	for _, channel := range channels {
+		if err := s.checkSlot(channel); err != nil {
+			s.logger().Warnf("Channels file %s: %v, skipping", s.channelsFile, err)
+			continue
+		}
+
+		loaded[channel] = struct{}{}
+
+		if _, ok := s.fileChannels[channel]; !ok {
+			added = append(added, channel)
+		}
+	}
+
+	var removed []string
+
+	for channel := range s.fileChannels {
+		if _, ok := loaded[channel]; !ok {
+			removed = append(removed, channel)
+		}
+	}
+
+	s.fileChannels = loaded
+
+	if len(added) == 0 && len(removed) == 0 {
+		return
+	}
+
+	sort.Strings(removed)
+
+	s.logger().Infof("Channels file %s changed: added %v, removed %v", s.channelsFile, added, removed)
+
+	s.Unsubscribe(removed...)
+	s.Subscribe(added...) // nolint:errcheck
+}
+
+// watchChannelsFile polls the channels file for changes.
+// Changes are only applied when the file contents is stable for a poll interval (to skip partial writes and bursts).
+func (s *RedisSubscriber) watchChannelsFile(applied []byte) {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(s.channelsFileInterval)
+	defer ticker.Stop()
+
+	var pending []byte
+
+	for {
+		select {
+		case <-s.shutdownCh:
+			return
+		case <-ticker.C:
+			data, err := os.ReadFile(s.channelsFile)
+
+			if err != nil {
+				s.logger().Warnf("Failed to read channels file: %v", err)
+				continue
+			}
+
+			if bytes.Equal(data, applied) {
+				pending = nil
+				continue
+			}
+
+			if pending == nil || !bytes.Equal(data, pending) {
+				pending = data
+				continue
+			}
+
+			s.loadChannelsFile(data)
+			applied, pending = data, nil
+		}
+	}
+}
@@ -0,0 +1,126 @@
+package pubsub
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/anycable/anycable-go/metrics"
+	"github.com/anycable/anycable-go/mocks"
+	"github.com/gomodule/redigo/redis"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseChannelsFile(t *testing.T) {
+	channels := parseChannelsFile([]byte("tenant_1\n\n# disabled\n  tenant_2  \r\n"))
+
+	assert.Equal(t, []string{"tenant_1", "tenant_2"}, channels)
+}
+
+func TestRedisDynamicChannels(t *testing.T) {
+	config := NewRedisConfig()
+	subscriber := NewRedisSubscriber(&mocks.Handler{}, metrics.NoopMetrics{}, &config)
+
+	conn := &fakeRedisConn{}
+	psc := redis.PubSubConn{Conn: conn}
+
+	require.NoError(t, subscriber.subscriptions.subscribe(psc, subscriber.channels()))
+
+	require.NoError(t, subscriber.Subscribe("tenant_2", "tenant_1"))
+	assert.Equal(t, []string{"__anycable__", "tenant_1", "tenant_2"}, subscriber.channels())
+
+	conn.sent = nil
+	require.NoError(t, subscriber.syncSubscriptions(psc))
+	assert.Equal(t, []string{"SUBSCRIBE", "SUBSCRIBE"}, conn.sent)
+
+	subscriber.Unsubscribe("tenant_1")
+
+	conn.sent = nil
+	require.NoError(t, subscriber.syncSubscriptions(psc))
+	assert.Equal(t, []string{"UNSUBSCRIBE"}, conn.sent)
+	assert.False(t, subscriber.subscriptions.tracked("tenant_1"))
+	assert.True(t, subscriber.subscriptions.tracked("tenant_2"))
+
+	// Nothing changed
+	conn.sent = nil
+	require.NoError(t, subscriber.syncSubscriptions(psc))
+	assert.Empty(t, conn.sent)
+
+	select {
+	case <-subscriber.channelsChangedCh:
+	default:
+		t.Fatal("Channels change notification is expected")
+	}
+}
+
+func TestRedisShardedDynamicChannels(t *testing.T) {
+	config := NewRedisConfig()
+	config.Sharded = true
+	config.Channel = "{anycable}:broadcasts"
+
+	subscriber := NewRedisSubscriber(&mocks.Handler{}, metrics.NoopMetrics{}, &config)
+
+	// Messages are received via the fake connection below, so the subscriber only waits for shutdown
+	subscriber.connect = func() error {
+		<-subscriber.shutdownCh
+		return nil
+	}
+
+	done := make(chan error, 1)
+
+	require.NoError(t, subscriber.Start(done))
+	defer subscriber.Shutdown() // nolint:errcheck
+
+	conn := &fakeRedisConn{}
+	psc := redis.PubSubConn{Conn: conn}
+
+	require.NoError(t, subscriber.subscriptions.subscribe(psc, subscriber.channels()))
+
+	assert.Error(t, subscriber.Subscribe("{anycable}:tenant_1", "tenant_2"))
+	require.NoError(t, subscriber.Subscribe("{anycable}:tenant_1"))
+
+	subscriber.loadChannelsFile([]byte("{anycable}:tenant_3\ntenant_4\n"))
+
+	assert.Equal(t, []string{"{anycable}:broadcasts", "{anycable}:tenant_1", "{anycable}:tenant_3"}, subscriber.channels())
+
+	conn.sent = nil
+	require.NoError(t, subscriber.syncSubscriptions(psc))
+	assert.Equal(t, []string{"SSUBSCRIBE", "SSUBSCRIBE"}, conn.sent)
+
+	select {
+	case err := <-done:
+		t.Fatalf("Subscriber has failed: %v", err)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestRedisWatchChannelsFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "channels.txt")
+	require.NoError(t, os.WriteFile(path, []byte("tenant_1\ntenant_2\n"), 0600))
+
+	config := NewRedisConfig()
+	config.ChannelsFile = path
+
+	subscriber := NewRedisSubscriber(&mocks.Handler{}, metrics.NoopMetrics{}, &config)
+	subscriber.channelsFileInterval = 10 * time.Millisecond
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+
+	subscriber.loadChannelsFile(data)
+	assert.Equal(t, []string{"__anycable__", "tenant_1", "tenant_2"}, subscriber.channels())
+
+	subscriber.wg.Add(1)
+	go subscriber.watchChannelsFile(data)
+
+	require.NoError(t, os.WriteFile(path, []byte("tenant_2\ntenant_3\n"), 0600))
+
+	assert.Eventually(t, func() bool {
+		channels := subscriber.dynamicChannelsList()
+		return len(channels) == 2 && channels[0] == "tenant_2" && channels[1] == "tenant_3"
+	}, time.Second, 10*time.Millisecond)
+
+	subscriber.Shutdown() // nolint:errcheck
+}
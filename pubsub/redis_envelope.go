@@ -0,0 +1,104 @@
+package pubsub
+
+import (
+	"container/list"
+	"encoding/json"
+	"errors"
+)
+
+const (
+	defaultRedisEnvelopeStreamsLimit = 10000
+)
+
+// redisEnvelope is a broadcast wrapper carrying a stream name and a monotonically increasing (per stream) epoch
+type redisEnvelope struct {
+	Stream  string          `json:"stream"`
+	Epoch   uint64          `json:"epoch"`
+	Payload json.RawMessage `json:"payload"`
+}
+
+// epochTracker keeps the last seen epochs for the most recently used streams (LRU)
+type epochTracker struct {
+	limit   int
+	streams map[string]*list.Element
+	lru     *list.List
+	memory  *redisMemory
+}
+
+type streamEpoch struct {
+	stream string
+	epoch  uint64
+}
+
+func newEpochTracker(limit int) *epochTracker {
+	if limit <= 0 {
+		limit = defaultRedisEnvelopeStreamsLimit
+	}
+
+	return &epochTracker{limit: limit, streams: make(map[string]*list.Element), lru: list.New()}
+}
+
+// Accept returns true and remembers the epoch if it's greater than the last seen one for the stream
+func (t *epochTracker) Accept(stream string, epoch uint64) bool {
+	if el, ok := t.streams[stream]; ok {
+		entry := el.Value.(*streamEpoch)
+
+		if epoch <= entry.epoch {
+			return false
+		}
+
+		entry.epoch = epoch
+		t.lru.MoveToFront(el)
+
+		return true
+	}
+
+	t.streams[stream] = t.lru.PushFront(&streamEpoch{stream: stream, epoch: epoch})
+	t.memory.reserve(len(stream) + redisMemoryItemOverhead)
+
+	// Shrink the tracker when the memory budget is approached
+	if t.lru.Len() > t.limit || (t.memory.pressure() && t.lru.Len() > 1) {
+		oldest := t.lru.Back()
+		evicted := oldest.Value.(*streamEpoch).stream
+
+		t.lru.Remove(oldest)
+		delete(t.streams, evicted)
+		t.memory.release(len(evicted) + redisMemoryItemOverhead)
+	}
+
+	return true
+}
+
+func parseRedisEnvelope(data []byte) (*redisEnvelope, error) {
+	var envelope redisEnvelope
+
+	if err := json.Unmarshal(data, &envelope); err != nil {
+		return nil, err
+	}
+
+	if envelope.Stream == "" || len(envelope.Payload) == 0 {
+		return nil, errors.New("stream and payload are required")
+	}
+
+	return &envelope, nil
+}
+
+// unwrapEnvelope extracts the inner payload from the envelope.
+// Returns false if the message must be dropped (malformed, duplicate or out-of-order).
+// Must be called from the receiving goroutine only.
+func (s *RedisSubscriber) unwrapEnvelope(channel string, data []byte) ([]byte, bool) {
+	envelope, err := parseRedisEnvelope(data)
+
+	if err != nil {
+		s.logger().Debugf("Failed to parse Redis broadcast envelope: %v", err)
+		s.drop(channel, data, "invalid_envelope")
+		return nil, false
+	}
+
+	if !s.epochs.Accept(envelope.Stream, envelope.Epoch) {
+		s.drop(channel, data, "stale_epoch")
+		return nil, false
+	}
+
+	return envelope.Payload, true
+}
@@ -0,0 +1,59 @@
+package pubsub
+
+import (
+	"testing"
+
+	"github.com/anycable/anycable-go/metrics"
+	"github.com/anycable/anycable-go/mocks"
+	"github.com/gomodule/redigo/redis"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEpochTracker(t *testing.T) {
+	tracker := newEpochTracker(2)
+
+	assert.True(t, tracker.Accept("a", 1))
+	assert.True(t, tracker.Accept("a", 2))
+	assert.False(t, tracker.Accept("a", 2))
+	assert.False(t, tracker.Accept("a", 1))
+
+	assert.True(t, tracker.Accept("b", 5))
+	assert.True(t, tracker.Accept("c", 1))
+
+	// "a" is evicted as the least recently used
+	assert.True(t, tracker.Accept("a", 1))
+	assert.False(t, tracker.Accept("c", 1))
+}
+
+func TestRedisReceiveEnvelope(t *testing.T) {
+	config := NewRedisConfig()
+	config.Envelope = true
+
+	handler := &mocks.Handler{}
+	subscriber := NewRedisSubscriber(handler, metrics.NoopMetrics{}, &config)
+
+	var reasons []string
+
+	subscriber.SetDeadLetterHandler(func(channel string, msg []byte, reason string) {
+		reasons = append(reasons, reason)
+	})
+
+	payload := `{"stream":"chat","data":"hi"}`
+	handler.On("HandlePubSub", []byte(payload))
+
+	envelope := `{"stream":"chat","epoch":1,"payload":` + payload + `}`
+	conn := &fakeRedisConn{reply: redisMessageReply("__anycable__", envelope), limit: 2}
+	done := make(chan error, 1)
+
+	subscriber.receive(redis.PubSubConn{Conn: conn}, done)
+	<-done
+
+	handler.AssertNumberOfCalls(t, "HandlePubSub", 1)
+	assert.Equal(t, []string{"stale_epoch"}, reasons)
+
+	conn = &fakeRedisConn{reply: redisMessageReply("__anycable__", payload), limit: 1}
+	subscriber.receive(redis.PubSubConn{Conn: conn}, done)
+
+	handler.AssertNumberOfCalls(t, "HandlePubSub", 1)
+	assert.Equal(t, []string{"stale_epoch", "invalid_envelope"}, reasons)
+}
@@ -0,0 +1,109 @@
+package pubsub
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	// Retry right away (after a short interval) without counting a reconnect attempt
+	redisErrorRetryFast = "retry-fast"
+	// Retry after a long interval without counting a reconnect attempt
+	redisErrorRetrySlow = "retry-slow"
+	// Stop reconnecting
+	redisErrorFatal = "fatal"
+
+	// How long to wait before retrying after an error matched by a retry-fast rule
+	redisRuleFastRetryInterval = time.Second
+	// The max number of retry-fast errors in a row retried without counting reconnect attempts
+	redisRuleMaxFastRetries = 10
+	// How long to wait before retrying after an error matched by a retry-slow rule
+	// (unless the cold retry interval is configured)
+	redisRuleSlowRetryInterval = 30 * time.Second
+)
+
+// redisErrorRule maps a connection error substring to the reconnect action
+type redisErrorRule struct {
+	pattern string
+	action  string
+}
+
+// parseRedisErrorRules parses a comma-separated list of "substring=action" pairs;
+// the order is preserved, so the first matching rule wins
+func parseRedisErrorRules(raw string) ([]redisErrorRule, error) {
+	var rules []redisErrorRule
+
+	for _, pair := range strings.Split(raw, ",") {
+		pattern, action, ok := strings.Cut(pair, "=")
+
+		pattern = strings.TrimSpace(pattern)
+		action = strings.TrimSpace(action)
+
+		if !ok || pattern == "" {
+			return nil, fmt.Errorf("invalid Redis error rule: %s", pair)
+		}
+
+		switch action {
+		case redisErrorRetryFast, redisErrorRetrySlow, redisErrorFatal:
+		default:
+			return nil, fmt.Errorf("unknown Redis error rule action: %s", action)
+		}
+
+		rules = append(rules, redisErrorRule{pattern: strings.ToLower(pattern), action: action})
+	}
+
+	return rules, nil
+}
+
+// errorRuleFor returns the first configured rule matching the connection error (case-insensitive);
+// retry-fast rules stop matching after the max number of fast retries in a row,
+// so the error is handled as a regular failure
+func (s *RedisSubscriber) errorRuleFor(err error) (redisErrorRule, bool) {
+	if err == nil || len(s.errorRules) == 0 {
+		s.ruleRetries = 0
+		return redisErrorRule{}, false
+	}
+
+	msg := strings.ToLower(err.Error())
+
+	for _, rule := range s.errorRules {
+		if !strings.Contains(msg, rule.pattern) {
+			continue
+		}
+
+		if rule.action == redisErrorRetryFast && s.ruleRetries >= redisRuleMaxFastRetries {
+			return redisErrorRule{}, false
+		}
+
+		return rule, true
+	}
+
+	s.ruleRetries = 0
+	return redisErrorRule{}, false
+}
+
+// retryByRule waits for the interval defined by the rule action and returns true
+// unless the subscriber is shutting down
+func (s *RedisSubscriber) retryByRule(rule redisErrorRule) bool {
+	interval := redisRuleFastRetryInterval
+
+	if rule.action == redisErrorRetrySlow {
+		interval = s.coldRetryInterval
+
+		if interval == 0 {
+			interval = redisRuleSlowRetryInterval
+		}
+	} else {
+		s.ruleRetries++
+	}
+
+	s.logger().Infof("Retrying Redis connection in %s (matched error rule %q: %s)", interval, rule.pattern, rule.action)
+
+	select {
+	case <-s.shutdownCh:
+		return false
+	case <-s.clock.after(interval):
+		return true
+	}
+}
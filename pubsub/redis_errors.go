@@ -0,0 +1,71 @@
+package pubsub
+
+import (
+	"errors"
+	"io"
+	"net"
+	"strings"
+	"syscall"
+
+	"github.com/gomodule/redigo/redis"
+)
+
+const (
+	// The connection has been closed by Redis (e.g., CLIENT KILL, server restart or idle timeout)
+	redisConnClosedByServer = "closed by server"
+	// The connection has been reset (e.g., Redis crashed or a proxy in between dropped the connection)
+	redisConnReset = "reset"
+	// The connection has been closed by the subscriber itself (e.g., on reconnect or shutdown)
+	redisConnClosedLocally = "closed locally"
+)
+
+// Error reply prefixes Redis uses when it's temporarily unable to serve commands
+// (e.g., loading the dataset after a restart), while the connection itself is fine
+var redisBusyErrors = []string{"LOADING", "BUSY", "TRYAGAIN", "MASTERDOWN"}
+
+// redisBusyError returns the error prefix if the error reply is a transient server-busy error
+func redisBusyError(err redis.Error) (string, bool) {
+	for _, prefix := range redisBusyErrors {
+		if strings.HasPrefix(string(err), prefix+" ") || string(err) == prefix {
+			return prefix, true
+		}
+	}
+
+	return "", false
+}
+
+// redisConnectionLoss returns the reason of the connection loss if the error is a routine connection loss
+// (which is expected to happen from time to time and is handled by reconnecting),
+// or false if the error is unexpected
+func redisConnectionLoss(err error) (string, bool) {
+	switch {
+	case errors.Is(err, io.EOF), errors.Is(err, io.ErrUnexpectedEOF):
+		return redisConnClosedByServer, true
+	case errors.Is(err, syscall.ECONNRESET), errors.Is(err, syscall.EPIPE):
+		return redisConnReset, true
+	case errors.Is(err, net.ErrClosed):
+		return redisConnClosedLocally, true
+	default:
+		return "", false
+	}
+}
+
+// logReceiveError logs routine connection losses at the info level (or debug, if the connection was closed by us)
+// and unexpected errors at the error level
+func (s *RedisSubscriber) logReceiveError(err error) {
+	reason, ok := redisConnectionLoss(err)
+
+	if !ok {
+		s.metrics.CounterIncrement(metricsRedisReceiveFailures)
+		s.logger().Errorf("Redis subscription error: %v", err)
+		return
+	}
+
+	if reason == redisConnClosedLocally {
+		s.logger().Debugf("Redis connection %s: %v", reason, err)
+		return
+	}
+
+	s.metrics.CounterIncrement(metricsRedisReceiveFailures)
+	s.logger().Infof("Redis connection %s (e.g., CLIENT KILL or server restart), reconnecting: %v", reason, err)
+}
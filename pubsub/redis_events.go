@@ -0,0 +1,82 @@
+package pubsub
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// Subscriber lifecycle event kinds (see RedisSubscriber.Events)
+const (
+	// Connecting to Redis (including reconnects)
+	RedisEventConnecting = "connecting"
+	// Connected to Redis (before subscribing)
+	RedisEventConnected = "connected"
+	// Subscription to the channel is confirmed
+	RedisEventSubscribed = "subscribed"
+	// Messages have been received since the previous event of this kind (emitted periodically)
+	RedisEventMessageReceived = "message_received"
+	// The connection is closed (Err is nil on graceful shutdown)
+	RedisEventDisconnected = "disconnected"
+	// The next reconnect attempt is scheduled in Delay
+	RedisEventReconnectScheduled = "reconnect_scheduled"
+	// The subscriber stopped reconnecting and reported the error
+	RedisEventGaveUp = "gave_up"
+)
+
+// RedisEvent is a subscriber lifecycle event
+type RedisEvent struct {
+	Kind string
+	At   time.Time
+	// The subscribed channel (subscribed)
+	Channel string
+	// The number of received messages (message_received)
+	Count int64
+	// The reason (disconnected, gave_up)
+	Err error
+	// The delay before the next attempt (reconnect_scheduled)
+	Delay time.Duration
+}
+
+// Events returns the channel of the subscriber lifecycle events (or nil if events are disabled, see EventsBufferSize).
+// Events are dropped if the channel buffer is full, so a slow consumer never blocks the subscriber.
+// The channel is closed on shutdown.
+func (s *RedisSubscriber) Events() <-chan RedisEvent {
+	if s.events == nil {
+		return nil
+	}
+
+	return s.events
+}
+
+func (s *RedisSubscriber) emitEvent(event RedisEvent) {
+	if s.events == nil {
+		return
+	}
+
+	event.At = time.Now()
+
+	select {
+	case s.events <- event:
+	default:
+		s.logger().Debugf("Redis events buffer is full, %s event is dropped", event.Kind)
+	}
+}
+
+// emitReceivedEvent reports the number of messages received since the previous call (if any)
+func (s *RedisSubscriber) emitReceivedEvent() {
+	if s.events == nil {
+		return
+	}
+
+	if count := atomic.SwapInt64(&s.receivedSinceEvent, 0); count > 0 {
+		s.emitEvent(RedisEvent{Kind: RedisEventMessageReceived, Count: count})
+	}
+}
+
+func (s *RedisSubscriber) closeEvents() {
+	if s.events == nil {
+		return
+	}
+
+	s.eventsCloseOnce.Do(func() { close(s.events) })
+}
@@ -0,0 +1,99 @@
+package pubsub
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+)
+
+const (
+	// Drop messages received while there is no handler
+	redisNoHandlerDrop = "drop"
+	// Keep messages received while there is no handler and deliver them as soon as the handler is set
+	redisNoHandlerBuffer = "buffer"
+
+	defaultRedisNoHandlerBufferSize = 1000
+)
+
+type redisPendingState struct {
+	mu       sync.Mutex
+	messages []redisMessage
+	warnOnce sync.Once
+}
+
+func validateRedisNoHandlerPolicy(policy string) error {
+	switch policy {
+	case redisNoHandlerDrop, redisNoHandlerBuffer:
+		return nil
+	default:
+		return fmt.Errorf("unknown Redis no handler policy: %s", policy)
+	}
+}
+
+// isNilHandler returns true if the handler is not set (including typed nil pointers, e.g., (*node.Node)(nil))
+func isNilHandler(node Handler) bool {
+	if node == nil {
+		return true
+	}
+
+	v := reflect.ValueOf(node)
+
+	return v.Kind() == reflect.Ptr && v.IsNil()
+}
+
+// handlerOrHold returns the current handler or, if it's not set yet, buffers or drops the message
+// (according to the policy) and returns false
+func (s *RedisSubscriber) handlerOrHold(channel string, data []byte, receivedAt time.Time) (Handler, bool) {
+	node := s.currentHandler()
+
+	if !isNilHandler(node) {
+		return node, true
+	}
+
+	s.pending.mu.Lock()
+	defer s.pending.mu.Unlock()
+
+	// The handler could have been set (and pending messages flushed) while we were waiting for the lock
+	node = s.currentHandler()
+
+	if !isNilHandler(node) {
+		return node, true
+	}
+
+	s.pending.warnOnce.Do(func() {
+		action := "dropped"
+
+		if s.noHandlerPolicy == redisNoHandlerBuffer {
+			action = "buffered"
+		}
+
+		s.logger().Warnf("Redis messages are received before the node is set, messages are %s until then", action)
+	})
+
+	if s.noHandlerPolicy == redisNoHandlerBuffer && len(s.pending.messages) < s.noHandlerBufferSize && !s.memory.pressure() {
+		s.pending.messages = append(s.pending.messages, redisMessage{channel: channel, data: data, receivedAt: receivedAt})
+		s.memory.reserve(messageSize(channel, data))
+		return nil, false
+	}
+
+	s.drop(channel, data, "no_handler")
+
+	return nil, false
+}
+
+// flushPendingLocked delivers the messages buffered while there was no handler (the pending lock must be held)
+func (s *RedisSubscriber) flushPendingLocked(node Handler) {
+	if len(s.pending.messages) == 0 {
+		return
+	}
+
+	s.logger().Infof("Delivering %d Redis messages received before the node was set", len(s.pending.messages))
+
+	for _, msg := range s.pending.messages {
+		s.deliver(node, msg.channel, msg.data, msg.receivedAt)
+		s.memory.release(messageSize(msg.channel, msg.data))
+	}
+
+	s.pending.messages = nil
+}
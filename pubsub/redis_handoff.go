@@ -0,0 +1,69 @@
+package pubsub
+
+import (
+	"context"
+	"sync/atomic"
+)
+
+// Subscriber handoff states (see Handoff)
+const (
+	// The subscriber is receiving messages as usual
+	RedisHandoffActive = "active"
+	// The subscriber is unsubscribing and delivering the remaining messages
+	RedisHandoffQuiescing = "quiescing"
+	// The subscriber has unsubscribed and delivered all the received messages
+	RedisHandoffHandedOff = "handed_off"
+)
+
+const (
+	redisHandoffActiveState int32 = iota
+	redisHandoffQuiescingState
+	redisHandoffHandedOffState
+)
+
+// Handoff quiesces the subscriber for a zero-downtime upgrade: it unsubscribes from all the channels,
+// stops reading from Redis, delivers the messages received so far and stops the subscriber
+// (without reporting an error, so the process could keep serving clients until they're handed off as well).
+//
+// The recommended sequence is:
+//   - start the new process and wait for it to subscribe (e.g., via OnSubscribed or its Status);
+//   - call Handoff on the old process, so both processes receive broadcasts in between and there is no gap;
+//   - wait for the old process to report the handed_off state (HandoffState or Status) and stop it.
+//
+// Broadcasts received by both processes during the overlap are delivered by both; that's fine
+// when the processes serve different client connections.
+// If the context is done before the subscriber is stopped, the context error is returned and
+// the state remains quiescing (the subscriber keeps stopping in the background).
+func (s *RedisSubscriber) Handoff(ctx context.Context) error {
+	atomic.CompareAndSwapInt32(&s.handoff, redisHandoffActiveState, redisHandoffQuiescingState)
+
+	s.logger().Info("Handing off Redis subscription")
+
+	stopped := make(chan struct{})
+
+	go func() {
+		s.Shutdown() // nolint:errcheck
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+		atomic.StoreInt32(&s.handoff, redisHandoffHandedOffState)
+		s.logger().Info("Redis subscription has been handed off")
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// HandoffState returns the current handoff state: active, quiescing or handed_off
+func (s *RedisSubscriber) HandoffState() string {
+	switch atomic.LoadInt32(&s.handoff) {
+	case redisHandoffQuiescingState:
+		return RedisHandoffQuiescing
+	case redisHandoffHandedOffState:
+		return RedisHandoffHandedOff
+	default:
+		return RedisHandoffActive
+	}
+}
@@ -0,0 +1,38 @@
+package pubsub
+
+import (
+	"context"
+
+	"github.com/gomodule/redigo/redis"
+)
+
+// Healthcheck verifies that Redis is available by sending a PING command.
+// If HealthcheckURL is configured, the check is performed against it (e.g., a read replica),
+// so the primary Redis is not loaded with extra connections.
+func (s *RedisSubscriber) Healthcheck(ctx context.Context) error {
+	c, err := s.healthcheckConn(ctx)
+
+	if err != nil {
+		return err
+	}
+
+	defer c.Close()
+
+	_, err = redis.DoContext(c, ctx, "PING")
+
+	return err
+}
+
+func (s *RedisSubscriber) healthcheckConn(ctx context.Context) (redis.Conn, error) {
+	dialOptions := append(s.dialOptions(), s.clientNameOptions(redisClientNameHealth)...)
+
+	if s.healthcheckURL != "" {
+		return redis.DialURLContext(ctx, s.healthcheckURL, dialOptions...)
+	}
+
+	if s.pool != nil {
+		return s.pool.GetContext(ctx)
+	}
+
+	return redis.DialURLContext(ctx, s.currentURL(), dialOptions...)
+}
@@ -0,0 +1,72 @@
+package pubsub
+
+import (
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+)
+
+const (
+	// Send PING commands over the subscription connection
+	redisKeepalivePing = "ping"
+	// Subscribe to and unsubscribe from a dedicated channel (for proxies not allowing PING in subscribed mode)
+	redisKeepaliveSubscribeNoop = "subscribe-noop"
+	// Do not send anything; rely on TCP keepalive to detect dead connections
+	redisKeepaliveNone = "none"
+
+	redisKeepaliveChannel = "__anycable_keepalive__"
+	// The connection is considered dead if there were no replies for this number of keepalive intervals
+	redisKeepaliveMissedIntervals = 3
+)
+
+var errRedisKeepaliveTimeout = errors.New("no replies from Redis")
+
+func validateRedisKeepaliveMode(mode string) error {
+	switch mode {
+	case redisKeepalivePing, redisKeepaliveSubscribeNoop, redisKeepaliveNone:
+		return nil
+	default:
+		return fmt.Errorf("unknown Redis keepalive mode: %s", mode)
+	}
+}
+
+// sendKeepalive sends a command to trigger a reply from Redis according to the keepalive mode
+func (s *RedisSubscriber) sendKeepalive(psc redis.PubSubConn) error {
+	switch s.keepaliveMode {
+	case redisKeepaliveSubscribeNoop:
+		if err := psc.Conn.Send("SUBSCRIBE", redisKeepaliveChannel); err != nil {
+			return err
+		}
+
+		if err := psc.Conn.Send("UNSUBSCRIBE", redisKeepaliveChannel); err != nil {
+			return err
+		}
+
+		return psc.Conn.Flush()
+	case redisKeepaliveNone:
+		return nil
+	default:
+		return psc.Ping("")
+	}
+}
+
+// touchReply remembers the time of the last reply from Redis
+func (s *RedisSubscriber) touchReply(now time.Time) {
+	atomic.StoreInt64(&s.lastReplyAt, now.UnixNano())
+}
+
+// keepaliveTimedOut returns true if there were no replies from Redis for too long,
+// i.e., the connection is likely dead even though writes succeed
+func (s *RedisSubscriber) keepaliveTimedOut(now time.Time) bool {
+	// Replies are not read while delivery is paused in the block mode
+	if s.keepaliveMode == redisKeepaliveNone || s.Paused() {
+		return false
+	}
+
+	lastReplyAt := time.Unix(0, atomic.LoadInt64(&s.lastReplyAt))
+
+	return now.Sub(lastReplyAt) > redisKeepaliveMissedIntervals*s.pingInterval*time.Second
+}
@@ -0,0 +1,125 @@
+package pubsub
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/gomodule/redigo/redis"
+)
+
+const redisKeyeventChannelPrefix = "__keyevent@"
+
+// Keyspace notification classes (see notify-keyspace-events) required to receive the events.
+// Unknown events are only checked for the keyevent notifications flag (E).
+var redisKeyeventClasses = map[string]string{
+	"del":         "g",
+	"expire":      "g",
+	"rename_from": "g",
+	"rename_to":   "g",
+	"copy_to":     "g",
+	"new":         "n",
+	"set":         "$",
+	"expired":     "x",
+	"evicted":     "e",
+}
+
+// parseKeyeventChannels returns the keyevent channels (__keyevent@<db>__:<event>) for the comma-separated
+// list of events and the database from the Redis URL
+func parseKeyeventChannels(raw string, uri *url.URL) ([]string, error) {
+	db := 0
+
+	if path := strings.Trim(uri.Path, "/"); path != "" {
+		var err error
+
+		if db, err = strconv.Atoi(path); err != nil {
+			return nil, fmt.Errorf("invalid Redis database: %s", path)
+		}
+	}
+
+	var channels []string
+
+	for _, event := range strings.Split(raw, ",") {
+		event = strings.TrimSpace(event)
+
+		if event == "" {
+			return nil, fmt.Errorf("invalid keyspace events list: %s", raw)
+		}
+
+		channels = append(channels, fmt.Sprintf("%s%d__:%s", redisKeyeventChannelPrefix, db, event))
+	}
+
+	return channels, nil
+}
+
+// keyeventName returns the event name from the keyevent channel
+func keyeventName(channel string) string {
+	if i := strings.Index(channel, "__:"); i >= 0 {
+		return channel[i+3:]
+	}
+
+	return channel
+}
+
+func (s *RedisSubscriber) isKeyeventChannel(channel string) bool {
+	for _, keyevent := range s.keyeventChannels {
+		if keyevent == channel {
+			return true
+		}
+	}
+
+	return false
+}
+
+// checkKeyspaceEvents warns if keyspace notifications required to receive the configured events are not enabled.
+// Failures are not fatal (e.g., CONFIG is usually disabled in managed Redis).
+func (s *RedisSubscriber) checkKeyspaceEvents(c redis.Conn) {
+	if len(s.keyeventChannels) == 0 {
+		return
+	}
+
+	reply, err := redis.Strings(redis.DoWithTimeout(c, s.commandTimeout, "CONFIG", "GET", "notify-keyspace-events"))
+
+	if err != nil || len(reply) != 2 {
+		s.logger().Debugf("Failed to verify Redis keyspace notifications settings: %v", err)
+		return
+	}
+
+	flags := reply[1]
+
+	if !strings.Contains(flags, "E") {
+		s.logger().Warnf("Redis keyevent notifications are disabled (notify-keyspace-events: %q), keyspace events won't be received", flags)
+		return
+	}
+
+	for _, channel := range s.keyeventChannels {
+		event := keyeventName(channel)
+		class, ok := redisKeyeventClasses[event]
+
+		if !ok || strings.Contains(flags, class) {
+			continue
+		}
+
+		// A is an alias for all the classes but n (new) and m (key miss)
+		if class != "n" && strings.Contains(flags, "A") {
+			continue
+		}
+
+		s.logger().Warnf("Redis keyspace notifications for %s events are disabled (notify-keyspace-events: %q, required class: %s)", event, flags, class)
+	}
+}
+
+// deliverKeyevent passes the keyspace notification to the handler (the payload is the key name)
+func (s *RedisSubscriber) deliverKeyevent(node Handler, channel string, data []byte) bool {
+	handler, ok := node.(KeyspaceHandler)
+
+	if !ok {
+		s.drop(channel, data, "no_keyspace_handler")
+		return false
+	}
+
+	handler.HandleKeyspaceEvent(keyeventName(channel), string(data))
+
+	return true
+}
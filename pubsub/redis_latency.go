@@ -0,0 +1,101 @@
+package pubsub
+
+import (
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// The number of the most recent handling latency samples to calculate percentiles from
+	redisLatencyWindowSize = 1024
+
+	metricsRedisHandleLatencyP50 = "redis_handle_latency_p50_ms"
+	metricsRedisHandleLatencyP99 = "redis_handle_latency_p99_ms"
+)
+
+// RedisLatencyStats contains percentiles of the time from receiving a message to the handler return
+// (calculated over the most recent messages)
+type RedisLatencyStats struct {
+	P50 time.Duration
+	P99 time.Duration
+	// The number of samples the percentiles are calculated from
+	Samples int
+}
+
+// redisLatencies is a ring buffer of the most recent latency samples
+type redisLatencies struct {
+	mu      sync.Mutex
+	samples []time.Duration
+	next    int
+}
+
+func (l *redisLatencies) record(d time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if len(l.samples) < redisLatencyWindowSize {
+		l.samples = append(l.samples, d)
+		return
+	}
+
+	l.samples[l.next] = d
+	l.next = (l.next + 1) % redisLatencyWindowSize
+}
+
+func (l *redisLatencies) stats() RedisLatencyStats {
+	l.mu.Lock()
+	sorted := append([]time.Duration(nil), l.samples...)
+	l.mu.Unlock()
+
+	if len(sorted) == 0 {
+		return RedisLatencyStats{}
+	}
+
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	return RedisLatencyStats{
+		P50:     percentile(sorted, 50),
+		P99:     percentile(sorted, 99),
+		Samples: len(sorted),
+	}
+}
+
+// percentile returns the nearest-rank percentile of the sorted samples
+func percentile(sorted []time.Duration, p int) time.Duration {
+	rank := (len(sorted)*p + 99) / 100
+
+	if rank < 1 {
+		rank = 1
+	}
+
+	return sorted[rank-1]
+}
+
+// checkLatency updates the latency metrics and warns when the p99 latency exceeds the threshold
+// (only state changes are logged)
+func (s *RedisSubscriber) checkLatency(stats RedisLatencyStats) {
+	s.metrics.GaugeSet(metricsRedisHandleLatencyP50, uint64(stats.P50.Milliseconds()))
+	s.metrics.GaugeSet(metricsRedisHandleLatencyP99, uint64(stats.P99.Milliseconds()))
+
+	if s.latencyThreshold <= 0 || stats.Samples == 0 {
+		return
+	}
+
+	slow := stats.P99 > s.latencyThreshold
+
+	var state int32
+
+	if slow {
+		state = 1
+	}
+
+	if atomic.SwapInt32(&s.latencySlow, state) != state {
+		if slow {
+			s.logger().Warnf("Redis messages handling p99 latency (%s) exceeds %s, the node is likely struggling", stats.P99, s.latencyThreshold)
+		} else {
+			s.logger().Infof("Redis messages handling p99 latency is back to normal (%s)", stats.P99)
+		}
+	}
+}
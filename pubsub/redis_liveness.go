@@ -0,0 +1,72 @@
+package pubsub
+
+import (
+	"net/http"
+	"sync/atomic"
+	"time"
+)
+
+// Ready returns true if the subscriber is connected to Redis and the subscription to the broadcasting channel
+// is confirmed (e.g., for readiness probes). If the delivery marker is configured, it must come back as well.
+func (s *RedisSubscriber) Ready() bool {
+	if !s.subscribed() {
+		return false
+	}
+
+	return s.marker == nil || s.marker.delivered()
+}
+
+// Live returns true as long as the subscriber keeps trying to deliver broadcasts: it hasn't given up reconnecting
+// and the receive loop is not stuck (e.g., for liveness probes or a watchdog).
+// Being disconnected and reconnecting is considered live.
+func (s *RedisSubscriber) Live() bool {
+	if atomic.LoadInt32(&s.gaveUp) == 1 || s.stopped() {
+		return false
+	}
+
+	return !(s.subscribed() && s.receiveStuck(time.Now()))
+}
+
+// ReadinessHandler returns an HTTP handler responding with 200 if the subscriber is ready and 503 otherwise
+func (s *RedisSubscriber) ReadinessHandler() http.Handler {
+	return healthStatusHandler(s.Ready)
+}
+
+// LivenessHandler returns an HTTP handler responding with 200 if the subscriber is live and 503 otherwise
+func (s *RedisSubscriber) LivenessHandler() http.Handler {
+	return healthStatusHandler(s.Live)
+}
+
+func (s *RedisSubscriber) subscribed() bool {
+	return s.subscriptions.snapshot()[s.channel] == RedisChannelSubscribed
+}
+
+// giveUp stops reconnecting and reports the error
+func (s *RedisSubscriber) giveUp(done chan error, err error) {
+	atomic.StoreInt32(&s.gaveUp, 1)
+	s.emitEvent(RedisEvent{Kind: RedisEventGaveUp, Err: err})
+	done <- err
+}
+
+// receiveStuck returns true if there were no replies for longer than the keepalive must have detected it,
+// i.e., the receive loop is not making progress (one extra interval is given to the keepalive to react)
+func (s *RedisSubscriber) receiveStuck(now time.Time) bool {
+	if !s.keepaliveTimedOut(now) {
+		return false
+	}
+
+	lastReplyAt := time.Unix(0, atomic.LoadInt64(&s.lastReplyAt))
+
+	return now.Sub(lastReplyAt) > (redisKeepaliveMissedIntervals+1)*s.pingInterval*time.Second
+}
+
+func healthStatusHandler(check func() bool) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		if check() {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+}
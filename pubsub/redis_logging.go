@@ -0,0 +1,87 @@
+package pubsub
+
+import (
+	"encoding/json"
+
+	"github.com/anycable/anycable-go/pubsub/logging"
+	"github.com/apex/log"
+)
+
+const (
+	defaultRedisLogContextKey = "context"
+	defaultRedisLogContext    = "pubsub"
+
+	redisCorrelationIDLogField = "correlation_id"
+	redisMasterLogField        = "master"
+)
+
+// newRedisLogger returns the subscriber logger with the context field attached
+// (apex/log-backed unless a custom logger is configured)
+func newRedisLogger(config *RedisConfig) logging.Logger {
+	if config.Logger != nil {
+		return config.Logger.WithField(config.LogContextKey, config.LogContext)
+	}
+
+	return logging.NewApexLogger(log.WithFields(log.Fields{config.LogContextKey: config.LogContext}))
+}
+
+// logger returns the subscriber logger (with the resolved master address attached in sentinel mode)
+func (s *RedisSubscriber) logger() logging.Logger {
+	if holder, ok := s.masterLog.Load().(redisLogHolder); ok {
+		return holder.logger
+	}
+
+	return s.log
+}
+
+// redisLogHolder wraps the logger to store it in atomic.Value (which requires a consistent concrete type)
+type redisLogHolder struct {
+	logger logging.Logger
+}
+
+// setLogMaster attaches the resolved master address to all the subsequent log entries (if enabled)
+func (s *RedisSubscriber) setLogMaster(addr string) {
+	if !s.sentinelLogMaster {
+		return
+	}
+
+	s.masterLog.Store(redisLogHolder{s.log.WithField(redisMasterLogField, addr)})
+}
+
+// messageLog returns a logger for the specified message.
+// If correlation ID key is configured and the payload carries it, the ID is attached to the logger.
+func (s *RedisSubscriber) messageLog(data []byte) logging.Logger {
+	if s.correlationIDKey == "" {
+		return s.logger()
+	}
+
+	if id := extractPayloadField(data, s.correlationIDKey); id != "" {
+		return s.logger().WithField(redisCorrelationIDLogField, id)
+	}
+
+	return s.logger()
+}
+
+// extractPayloadField returns the value of the top-level string field of the JSON payload
+// or an empty string if the payload is not a JSON object or doesn't contain the field
+func extractPayloadField(data []byte, key string) string {
+	var fields map[string]json.RawMessage
+
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return ""
+	}
+
+	raw, ok := fields[key]
+
+	if !ok {
+		return ""
+	}
+
+	var id string
+
+	if err := json.Unmarshal(raw, &id); err != nil {
+		return ""
+	}
+
+	return id
+}
@@ -0,0 +1,126 @@
+package pubsub
+
+import (
+	"bytes"
+	"context"
+	"sync"
+	"time"
+
+	nanoid "github.com/matoous/go-nanoid"
+)
+
+const (
+	defaultRedisMarkerTimeout = 5
+
+	metricsRedisMarkerFailures = "redis_marker_failures_total"
+)
+
+// redisMarker tracks the end-to-end delivery check: after subscribing, a unique marker message is published
+// to the broadcasting channel, and delivery is confirmed once the marker comes back
+type redisMarker struct {
+	prefix []byte
+
+	mu        sync.Mutex
+	token     []byte
+	confirmed chan struct{}
+}
+
+func newRedisMarker(prefix string) *redisMarker {
+	if prefix == "" {
+		return nil
+	}
+
+	return &redisMarker{prefix: []byte(prefix)}
+}
+
+// reset generates a new marker to wait for (the previous one is ignored if it comes back late)
+func (m *redisMarker) reset() ([]byte, <-chan struct{}, error) {
+	id, err := nanoid.Nanoid()
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.token = append(append([]byte{}, m.prefix...), ":"+id...)
+	m.confirmed = make(chan struct{})
+
+	return m.token, m.confirmed, nil
+}
+
+// match returns true if the message is a marker (own or other nodes'); the own pending marker is confirmed
+func (m *redisMarker) match(data []byte) bool {
+	if !bytes.HasPrefix(data, m.prefix) {
+		return false
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.confirmed != nil && bytes.Equal(data, m.token) {
+		select {
+		case <-m.confirmed:
+		default:
+			close(m.confirmed)
+		}
+	}
+
+	return true
+}
+
+// delivered returns true if the current marker has come back
+func (m *redisMarker) delivered() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.confirmed == nil {
+		return false
+	}
+
+	select {
+	case <-m.confirmed:
+		return true
+	default:
+		return false
+	}
+}
+
+// publishMarker publishes a new marker to the broadcasting channel and waits for it to come back
+// (the subscriber is not considered ready until then)
+func (s *RedisSubscriber) publishMarker() {
+	defer s.wg.Done()
+
+	token, confirmed, err := s.marker.reset()
+
+	if err != nil {
+		s.logger().Errorf("Failed to generate Redis delivery marker: %v", err)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), s.markerTimeout)
+	defer cancel()
+
+	startedAt := time.Now()
+
+	if err = s.roundTripPublish(ctx, s.channel, token); err != nil {
+		s.metrics.CounterIncrement(metricsRedisMarkerFailures)
+		s.logger().Warnf("Failed to publish Redis delivery marker: %v", err)
+		return
+	}
+
+	select {
+	case <-confirmed:
+		s.logger().WithField("duration", time.Since(startedAt)).Debug("Redis delivery marker received")
+	case <-ctx.Done():
+		s.metrics.CounterIncrement(metricsRedisMarkerFailures)
+		s.logger().Warnf("Redis delivery marker hasn't been received in %s", s.markerTimeout)
+	case <-s.shutdownCh:
+	}
+}
+
+// isMarker returns true if the message is a delivery marker, which must not be passed to the node
+func (s *RedisSubscriber) isMarker(channel string, data []byte) bool {
+	return s.marker != nil && channel == s.channel && s.marker.match(data)
+}
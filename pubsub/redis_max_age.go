@@ -0,0 +1,77 @@
+package pubsub
+
+import (
+	"encoding/json"
+	"math"
+	"time"
+)
+
+const (
+	// Numeric timestamps greater than this are treated as Unix milliseconds (e.g., JavaScript Date.now())
+	redisTimestampMillisThreshold = 1e12
+
+	metricsRedisExpiredMsg = "redis_expired_msg_total"
+)
+
+// extractPayloadTime returns the time from the payload field.
+// The field could be either a number (Unix time in seconds, with fractions, or milliseconds) or an RFC 3339 string.
+func extractPayloadTime(data []byte, key string) (time.Time, bool) {
+	var fields map[string]json.RawMessage
+
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return time.Time{}, false
+	}
+
+	raw, ok := fields[key]
+
+	if !ok {
+		return time.Time{}, false
+	}
+
+	var num float64
+
+	if err := json.Unmarshal(raw, &num); err == nil {
+		if num >= redisTimestampMillisThreshold {
+			return time.UnixMilli(int64(num)), true
+		}
+
+		sec, frac := math.Modf(num)
+
+		return time.Unix(int64(sec), int64(frac*1e9)), true
+	}
+
+	var str string
+
+	if err := json.Unmarshal(raw, &str); err != nil {
+		return time.Time{}, false
+	}
+
+	ts, err := time.Parse(time.RFC3339Nano, str)
+
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	return ts, true
+}
+
+// isExpired returns true if the message has been broadcasted longer than the max age ago.
+// Messages without a valid timestamp never expire.
+func (s *RedisSubscriber) isExpired(data []byte) bool {
+	broadcastAt, ok := extractPayloadTime(data, s.maxAgeKey)
+
+	if !ok {
+		return false
+	}
+
+	age := time.Since(broadcastAt)
+
+	if age <= s.maxAge {
+		return false
+	}
+
+	s.metrics.CounterIncrement(metricsRedisExpiredMsg)
+	s.logger().Debugf("Expired Redis message skipped (age: %s, max age: %s)", age.Round(time.Millisecond), s.maxAge)
+
+	return true
+}
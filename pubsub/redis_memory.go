@@ -0,0 +1,83 @@
+package pubsub
+
+import (
+	"sync/atomic"
+)
+
+const (
+	// Estimated per-item overhead (struct, map and list entries) in bytes
+	redisMemoryItemOverhead = 64
+	// The budget is considered approached when the usage is above this share (percents)
+	redisMemoryPressureThreshold = 90
+
+	metricsRedisMemoryUsed = "redis_memory_used_bytes"
+)
+
+// RedisMemoryStats contains the estimated memory usage of the subscriber internal buffers
+// (dispatch queues, dedup and epoch caches, dead letters and messages received before the node is set)
+type RedisMemoryStats struct {
+	// Memory budget in bytes (0 means unlimited)
+	Budget int64
+	// Estimated memory used by buffers in bytes
+	Used int64
+}
+
+// redisMemory tracks the estimated size of the buffered data.
+// The zero value (or nil) is an unlimited budget.
+type redisMemory struct {
+	budget int64
+	used   int64
+}
+
+func newRedisMemory(budget int64) *redisMemory {
+	return &redisMemory{budget: budget}
+}
+
+func (m *redisMemory) reserve(size int) {
+	if m == nil {
+		return
+	}
+
+	atomic.AddInt64(&m.used, int64(size))
+}
+
+func (m *redisMemory) release(size int) {
+	if m == nil {
+		return
+	}
+
+	atomic.AddInt64(&m.used, -int64(size))
+}
+
+// pressure returns true if the usage is close to the budget,
+// so buffers must evict or drop data more aggressively
+func (m *redisMemory) pressure() bool {
+	if m == nil || m.budget <= 0 {
+		return false
+	}
+
+	return atomic.LoadInt64(&m.used)*100 >= m.budget*redisMemoryPressureThreshold
+}
+
+func (m *redisMemory) Stats() RedisMemoryStats {
+	if m == nil {
+		return RedisMemoryStats{}
+	}
+
+	return RedisMemoryStats{Budget: m.budget, Used: atomic.LoadInt64(&m.used)}
+}
+
+func messageSize(channel string, data []byte) int {
+	return len(channel) + len(data) + redisMemoryItemOverhead
+}
+
+// checkMemory logs a warning if the memory budget is approached
+func (s *RedisSubscriber) checkMemory(stats RedisMemoryStats) {
+	s.metrics.GaugeSet(metricsRedisMemoryUsed, uint64(stats.Used))
+
+	if !s.memory.pressure() {
+		return
+	}
+
+	s.logger().Warnf("Redis subscriber buffers are close to the memory budget (%d of %d bytes), dropping and evicting buffered data more aggressively", stats.Used, stats.Budget)
+}
@@ -0,0 +1,46 @@
+package pubsub
+
+import (
+	"github.com/anycable/anycable-go/metrics"
+)
+
+// instrumenterOrNoop returns the instrumenter or a no-op one if it's nil,
+// so embedders not interested in metrics don't have to provide any implementation
+func instrumenterOrNoop(instrumenter metrics.Instrumenter) metrics.Instrumenter {
+	if instrumenter == nil {
+		return metrics.NoopMetrics{}
+	}
+
+	return instrumenter
+}
+
+// registerCounter registers the counter with static tags (if any and if supported by the instrumenter)
+func registerCounter(instrumenter metrics.Instrumenter, tags map[string]string, name string, desc string) {
+	if tagged, ok := instrumenter.(metrics.TaggedInstrumenter); ok && len(tags) > 0 {
+		tagged.RegisterTaggedCounter(name, desc, tags)
+		return
+	}
+
+	instrumenter.RegisterCounter(name, desc)
+}
+
+// registerGauge registers the gauge with static tags (if any and if supported by the instrumenter)
+func registerGauge(instrumenter metrics.Instrumenter, tags map[string]string, name string, desc string) {
+	if tagged, ok := instrumenter.(metrics.TaggedInstrumenter); ok && len(tags) > 0 {
+		tagged.RegisterTaggedGauge(name, desc, tags)
+		return
+	}
+
+	instrumenter.RegisterGauge(name, desc)
+}
+
+// metricKey returns the key to report the metric registered via registerCounter or registerGauge with:
+// tagged metrics are registered as separate series, so subscribers sharing the instrumenter
+// with different tags don't override each other's metrics
+func metricKey(instrumenter metrics.Instrumenter, tags map[string]string, name string) string {
+	if _, ok := instrumenter.(metrics.TaggedInstrumenter); ok && len(tags) > 0 {
+		return metrics.SeriesKey(name, tags)
+	}
+
+	return name
+}
@@ -0,0 +1,76 @@
+package pubsub
+
+import (
+	"sync"
+	"time"
+)
+
+// redisOutage tracks the current Redis outage (from the first connection failure till the subscription is restored),
+// so only state transitions are logged prominently: the first failure and the recovery
+type redisOutage struct {
+	mu        sync.Mutex
+	startedAt time.Time
+	failures  int
+}
+
+// fail registers a connection failure and returns true if it started a new outage
+func (o *redisOutage) fail(now time.Time) bool {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	o.failures++
+
+	if !o.startedAt.IsZero() {
+		return false
+	}
+
+	o.startedAt = now
+
+	return true
+}
+
+// active returns true if there is an ongoing outage
+func (o *redisOutage) active() bool {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	return !o.startedAt.IsZero()
+}
+
+// recover finishes the current outage (if any) and returns the number of failures and the downtime
+func (o *redisOutage) recover(now time.Time) (int, time.Duration, bool) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if o.startedAt.IsZero() {
+		return 0, 0, false
+	}
+
+	failures, downtime := o.failures, now.Sub(o.startedAt)
+
+	o.startedAt = time.Time{}
+	o.failures = 0
+
+	return failures, downtime, true
+}
+
+// reconnectLogf returns the log function for reconnect progress messages:
+// they're only logged at the info level until the outage is started (i.e., once per outage)
+func (s *RedisSubscriber) reconnectLogf(first bool) func(string, ...interface{}) {
+	if s.outage.active() && !first {
+		return s.logger().Debugf
+	}
+
+	return s.logger().Infof
+}
+
+// logRecovery logs the end of the outage (if any) when the subscription is restored
+func (s *RedisSubscriber) logRecovery() {
+	failures, downtime, ok := s.outage.recover(time.Now())
+
+	if !ok {
+		return
+	}
+
+	s.logger().WithField("downtime", downtime).Infof("Redis connection recovered after %d failed attempts", failures)
+}
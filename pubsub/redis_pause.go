@@ -0,0 +1,96 @@
+package pubsub
+
+import (
+	"fmt"
+	"sync"
+)
+
+const (
+	// Stop reading from Redis while paused (Redis buffers messages)
+	redisPauseBlock = "block"
+	// Keep reading from Redis and drop messages while paused
+	redisPauseDrop = "drop"
+
+	metricsRedisPaused = "redis_paused"
+)
+
+type redisPauseState struct {
+	mu       sync.Mutex
+	paused   bool
+	resumeCh chan struct{}
+}
+
+func validateRedisPauseMode(mode string) error {
+	switch mode {
+	case redisPauseBlock, redisPauseDrop:
+		return nil
+	default:
+		return fmt.Errorf("unknown Redis pause mode: %s", mode)
+	}
+}
+
+// Pause stops delivering messages to the node without disconnecting from Redis.
+// Depending on the pause mode, the subscriber either stops reading from Redis (so messages are buffered by Redis)
+// or drops incoming messages.
+func (s *RedisSubscriber) Pause() {
+	s.pause.mu.Lock()
+	defer s.pause.mu.Unlock()
+
+	if s.pause.paused {
+		return
+	}
+
+	s.pause.paused = true
+	s.pause.resumeCh = make(chan struct{})
+
+	s.metrics.GaugeSet(metricsRedisPaused, 1)
+	s.logger().Infof("Redis messages delivery is paused (mode: %s)", s.pauseMode)
+}
+
+// Resume restores messages delivery after Pause
+func (s *RedisSubscriber) Resume() {
+	s.pause.mu.Lock()
+	defer s.pause.mu.Unlock()
+
+	if !s.pause.paused {
+		return
+	}
+
+	s.pause.paused = false
+	close(s.pause.resumeCh)
+
+	s.metrics.GaugeSet(metricsRedisPaused, 0)
+	s.logger().Info("Redis messages delivery is resumed")
+}
+
+// Paused returns true if messages delivery is paused
+func (s *RedisSubscriber) Paused() bool {
+	s.pause.mu.Lock()
+	defer s.pause.mu.Unlock()
+
+	return s.pause.paused
+}
+
+// awaitDelivery returns true if the message could be delivered right away or after the subscriber is resumed
+// and false if the message must be dropped (in the drop mode or if the subscriber is shutting down while paused)
+func (s *RedisSubscriber) awaitDelivery() bool {
+	s.pause.mu.Lock()
+	paused := s.pause.paused
+	resumeCh := s.pause.resumeCh
+	s.pause.mu.Unlock()
+
+	if !paused {
+		return true
+	}
+
+	if s.pauseMode == redisPauseDrop {
+		return false
+	}
+
+	select {
+	case <-resumeCh:
+		return true
+	case <-s.shutdownCh:
+		return false
+	}
+}
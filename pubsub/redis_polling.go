@@ -0,0 +1,208 @@
+package pubsub
+
+import (
+	"bytes"
+	"sync"
+	"time"
+
+	"github.com/anycable/anycable-go/metrics"
+	"github.com/anycable/anycable-go/pubsub/logging"
+	"github.com/gomodule/redigo/redis"
+)
+
+const (
+	defaultRedisPollInterval = 1
+	defaultRedisPollSize     = 100
+	// How often to retry pub/sub while polling if the cold retry interval is not set
+	redisPollRetryInterval = 30 * time.Second
+
+	metricsRedisPolling   = "redis_polling"
+	metricsRedisPolledMsg = "redis_polled_msg_total"
+)
+
+// redisPollingConfig contains the polling fallback settings
+type redisPollingConfig struct {
+	// Redis list with recent broadcasts to poll
+	Key string
+	// How often to read the list
+	Interval time.Duration
+	// The max number of recent broadcasts to read at once
+	Size int
+	// Timeout for reading the list
+	CommandTimeout time.Duration
+}
+
+// redisPoller reads broadcasts from a Redis list on the interval when pub/sub is unavailable.
+// The list is expected to be maintained the same way as the replay list (RPUSH + LTRIM key -N -1).
+type redisPoller struct {
+	config  redisPollingConfig
+	dial    func() (redis.Conn, error)
+	deliver func(data []byte)
+	metrics metrics.Instrumenter
+	logger  func() logging.Logger
+
+	mu sync.Mutex
+	// Closed to stop the current polling loop (nil if not polling)
+	stopCh chan struct{}
+	wg     sync.WaitGroup
+}
+
+func newRedisPoller(config redisPollingConfig, dial func() (redis.Conn, error), deliver func(data []byte), metrics metrics.Instrumenter, logger func() logging.Logger) *redisPoller {
+	return &redisPoller{config: config, dial: dial, deliver: deliver, metrics: metrics, logger: logger}
+}
+
+// Enabled returns true if the polling fallback is configured
+func (p *redisPoller) Enabled() bool {
+	return p.config.Key != ""
+}
+
+// Start switches to polling (if not yet)
+func (p *redisPoller) Start() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.stopCh != nil {
+		return
+	}
+
+	p.stopCh = make(chan struct{})
+
+	p.metrics.GaugeSet(metricsRedisPolling, 1)
+	p.logger().Warnf("Redis pub/sub is unavailable, switching to polling Redis list %s every %s", p.config.Key, p.config.Interval)
+
+	p.wg.Add(1)
+	go p.run(p.stopCh)
+}
+
+// Stop stops polling (if started) and waits for the current poll to complete
+func (p *redisPoller) Stop() {
+	p.mu.Lock()
+
+	if p.stopCh == nil {
+		p.mu.Unlock()
+		return
+	}
+
+	close(p.stopCh)
+	p.stopCh = nil
+
+	p.metrics.GaugeSet(metricsRedisPolling, 0)
+	p.logger().Info("Redis pub/sub is restored, stopped polling")
+
+	p.mu.Unlock()
+
+	p.wg.Wait()
+}
+
+// Active returns true if polling is in progress
+func (p *redisPoller) Active() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.stopCh != nil
+}
+
+// run reads the list on the interval and delivers new messages until stopped.
+// The first poll delivers all the messages in the list (enable deduplication or envelopes to skip
+// the ones delivered before pub/sub went down).
+func (p *redisPoller) run(stopCh chan struct{}) {
+	defer p.wg.Done()
+
+	var c redis.Conn
+	var last []byte
+
+	defer func() {
+		if c != nil {
+			c.Close()
+		}
+	}()
+
+	ticker := time.NewTicker(p.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		if c == nil {
+			var err error
+
+			if c, err = p.dial(); err != nil {
+				p.logger().Debugf("Failed to connect to Redis for polling: %v", err)
+				c = nil
+			}
+		}
+
+		if c != nil {
+			var err error
+
+			if last, err = p.pollOnce(c, last, stopCh); err != nil {
+				p.logger().Debugf("Failed to poll Redis list %s: %v", p.config.Key, err)
+				c.Close()
+				c = nil
+			}
+		}
+
+		select {
+		case <-stopCh:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// pollOnce reads the list and delivers the messages added after the last seen one;
+// it returns the new last seen message
+func (p *redisPoller) pollOnce(c redis.Conn, last []byte, stopCh chan struct{}) ([]byte, error) {
+	messages, err := redis.ByteSlices(redis.DoWithTimeout(c, p.config.CommandTimeout, "LRANGE", p.config.Key, -p.config.Size, -1))
+
+	if err != nil {
+		return last, err
+	}
+
+	for _, data := range newPolledMessages(messages, last) {
+		select {
+		case <-stopCh:
+			return last, nil
+		default:
+		}
+
+		p.metrics.CounterIncrement(metricsRedisPolledMsg)
+		p.deliver(data)
+	}
+
+	if len(messages) > 0 {
+		last = messages[len(messages)-1]
+	}
+
+	return last, nil
+}
+
+// newPolledMessages returns the messages added to the list after the last seen one
+// (all the messages if the last seen one is not in the list anymore)
+func newPolledMessages(messages [][]byte, last []byte) [][]byte {
+	if last == nil {
+		return messages
+	}
+
+	for i := len(messages) - 1; i >= 0; i-- {
+		if bytes.Equal(messages[i], last) {
+			return messages[i+1:]
+		}
+	}
+
+	return messages
+}
+
+// Polling returns true if the subscriber has fallen back to polling the broadcasts list
+func (s *RedisSubscriber) Polling() bool {
+	return s.poller.Active()
+}
+
+// dialPoll connects to Redis to poll the broadcasts list (PollURL is used if set)
+func (s *RedisSubscriber) dialPoll() (redis.Conn, error) {
+	url := s.pollURL
+
+	if url == "" {
+		url = s.currentURL()
+	}
+
+	return redis.DialURL(url, append(s.dialOptions(), s.clientNameOptions(redisClientNameAux)...)...)
+}
@@ -0,0 +1,169 @@
+package pubsub
+
+import (
+	"errors"
+	"math/rand"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/anycable/anycable-go/metrics"
+	"github.com/anycable/anycable-go/mocks"
+	"github.com/gomodule/redigo/redis"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestRedisPoller(config redisPollingConfig, dial func() (redis.Conn, error)) (*redisPoller, *metrics.Metrics, func() []string) {
+	m := metrics.NewMetrics(nil, 0)
+	m.RegisterCounter(metricsRedisPolledMsg, "")
+	m.RegisterGauge(metricsRedisPolling, "")
+
+	var mu sync.Mutex
+	var received []string
+
+	poller := newRedisPoller(config, dial, func(data []byte) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		received = append(received, string(data))
+	}, m, testRedisLogger())
+
+	return poller, m, func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+
+		return append([]string(nil), received...)
+	}
+}
+
+func TestNewPolledMessages(t *testing.T) {
+	messages := [][]byte{[]byte("a"), []byte("b"), []byte("c")}
+
+	assert.Equal(t, messages, newPolledMessages(messages, nil))
+	assert.Equal(t, messages[2:], newPolledMessages(messages, []byte("b")))
+	assert.Empty(t, newPolledMessages(messages, []byte("c")))
+	// The last seen message has been trimmed from the list
+	assert.Equal(t, messages, newPolledMessages(messages, []byte("z")))
+}
+
+func TestRedisPollOnce(t *testing.T) {
+	poller, m, received := newTestRedisPoller(redisPollingConfig{Key: "__anycable_poll__", Size: 10}, nil)
+
+	conn := &listRedisConn{items: [][]byte{[]byte("first"), []byte("second")}}
+	stopCh := make(chan struct{})
+
+	last, err := poller.pollOnce(conn, nil, stopCh)
+	require.NoError(t, err)
+	assert.Equal(t, []interface{}{"__anycable_poll__", -10, -1}, conn.args)
+
+	conn.items = append(conn.items, []byte("third"))
+
+	last, err = poller.pollOnce(conn, last, stopCh)
+	require.NoError(t, err)
+	assert.Equal(t, "third", string(last))
+
+	assert.Equal(t, []string{"first", "second", "third"}, received())
+	assert.Equal(t, uint64(3), m.Counter(metricsRedisPolledMsg).Value())
+}
+
+func TestRedisPoller(t *testing.T) {
+	config := redisPollingConfig{Key: "__anycable_poll__", Size: 10, Interval: 10 * time.Millisecond}
+
+	t.Run("Polls until stopped", func(t *testing.T) {
+		conn := &listRedisConn{items: [][]byte{[]byte("first"), []byte("second")}}
+
+		poller, m, received := newTestRedisPoller(config, func() (redis.Conn, error) { return conn, nil })
+
+		assert.True(t, poller.Enabled())
+		assert.False(t, poller.Active())
+
+		poller.Start()
+		// Starting again is a no-op
+		poller.Start()
+
+		assert.True(t, poller.Active())
+		assert.Equal(t, uint64(1), m.Gauge(metricsRedisPolling).Value())
+
+		assert.Eventually(t, func() bool { return len(received()) == 2 }, time.Second, 10*time.Millisecond)
+
+		poller.Stop()
+
+		assert.False(t, poller.Active())
+		assert.Equal(t, uint64(0), m.Gauge(metricsRedisPolling).Value())
+		assert.Equal(t, []string{"first", "second"}, received())
+	})
+
+	t.Run("Retries to connect", func(t *testing.T) {
+		var attempts int32
+		var mu sync.Mutex
+
+		poller, _, _ := newTestRedisPoller(config, func() (redis.Conn, error) {
+			mu.Lock()
+			defer mu.Unlock()
+
+			attempts++
+			return nil, errors.New("connection refused")
+		})
+
+		poller.Start()
+
+		assert.Eventually(t, func() bool {
+			mu.Lock()
+			defer mu.Unlock()
+
+			return attempts > 1
+		}, time.Second, 10*time.Millisecond)
+
+		poller.Stop()
+	})
+
+	t.Run("Disabled", func(t *testing.T) {
+		poller, _, _ := newTestRedisPoller(redisPollingConfig{}, nil)
+
+		assert.False(t, poller.Enabled())
+
+		// Stopping a poller which hasn't been started is a no-op
+		poller.Stop()
+	})
+}
+
+func TestRedisPollingFallback(t *testing.T) {
+	config := NewRedisConfig()
+	config.PollKey = "__anycable_poll__"
+	config.PollURL = "redis://127.0.0.1:1"
+
+	m := metrics.NewMetrics(nil, 0)
+	subscriber := NewRedisSubscriber(&mocks.Handler{}, m, &config)
+	subscriber.connect = func() error {
+		// Pub/sub connection is established once polling is started
+		if subscriber.Polling() {
+			<-subscriber.shutdownCh
+			return nil
+		}
+
+		return errors.New("connection refused")
+	}
+	subscriber.clock = redisClock{
+		after: func(d time.Duration) <-chan time.Time {
+			ch := make(chan time.Time, 1)
+			ch <- time.Now()
+			return ch
+		},
+		intn: rand.New(rand.NewSource(42)).Intn, // #nosec
+	}
+
+	done := make(chan error, 1)
+
+	require.NoError(t, subscriber.Start(done))
+
+	require.Eventually(t, subscriber.Polling, time.Second, 10*time.Millisecond)
+	assert.True(t, subscriber.Status().Polling)
+	assert.Equal(t, uint64(1), m.Gauge(metricsRedisPolling).Value())
+
+	require.NoError(t, subscriber.Shutdown())
+
+	assert.False(t, subscriber.Polling())
+	assert.Equal(t, uint64(0), m.Gauge(metricsRedisPolling).Value())
+	assert.Empty(t, done)
+}
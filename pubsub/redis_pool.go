@@ -0,0 +1,145 @@
+package pubsub
+
+import (
+	"errors"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+)
+
+const (
+	defaultRedisPoolMaxActive   = 4
+	defaultRedisPoolMaxIdle     = 1
+	defaultRedisPoolIdleTimeout = 240
+	// Refresh pool metrics interval
+	redisStatsInterval = 5 * time.Second
+
+	metricsRedisPoolActive = "redis_pool_active_num"
+	metricsRedisPoolIdle   = "redis_pool_idle_num"
+	metricsRedisPoolWaits  = "redis_pool_waits_total"
+)
+
+// RedisPoolStats contains auxiliary connections pool statistics
+type RedisPoolStats struct {
+	// The number of connections in the pool (both idle and in use)
+	ActiveCount int
+	// The number of idle connections in the pool
+	IdleCount int
+	// The total number of connections waited for
+	WaitCount int64
+	// The total time spent waiting for a connection
+	WaitDuration time.Duration
+}
+
+// RedisStatus describes the current state of the subscriber
+type RedisStatus struct {
+	// The number of failed reconnect attempts since the last successful connection
+	ReconnectAttempts int
+	// The max number of reconnect attempts before giving up
+	MaxReconnectAttempts int
+	// Whether messages delivery is paused
+	Paused bool
+	// Whether broadcasts are polled from the Redis list since pub/sub is unavailable
+	Polling bool
+	// Handoff state: active, quiescing or handed_off (see Handoff)
+	Handoff string
+	// Subscription states per channel (pending, subscribed or failed)
+	Channels map[string]string
+	Pool     RedisPoolStats
+	Dispatch RedisDispatchStats
+	Memory   RedisMemoryStats
+	// Rolling percentiles of the time from receiving a message to the handler return
+	HandleLatency RedisLatencyStats
+}
+
+// newPool creates a pool of connections for auxiliary commands (i.e., everything but pub/sub).
+// The pub/sub connection is never borrowed from the pool to avoid contention with auxiliary commands
+// (and since it's blocked in the subscribed mode anyway).
+func (s *RedisSubscriber) newPool() *redis.Pool {
+	return &redis.Pool{
+		MaxIdle:     s.poolMaxIdle,
+		MaxActive:   s.poolMaxActive,
+		IdleTimeout: s.poolIdleTimeout,
+		Wait:        true,
+		Dial: func() (redis.Conn, error) {
+			return redis.DialURL(s.currentURL(), append(s.dialOptions(), s.clientNameOptions(redisClientNameAux)...)...)
+		},
+		TestOnBorrow: func(c redis.Conn, t time.Time) error {
+			if s.sentinels != "" {
+				if !s.testRole(c, redisRoleMaster) {
+					return errors.New("Failed master role check")
+				}
+
+				return nil
+			}
+
+			if time.Since(t) < time.Minute {
+				return nil
+			}
+
+			_, err := c.Do("PING")
+			return err
+		},
+	}
+}
+
+// Status returns the current subscriber status
+func (s *RedisSubscriber) Status() RedisStatus {
+	status := RedisStatus{
+		ReconnectAttempts:    s.ReconnectAttempts(),
+		MaxReconnectAttempts: maxReconnectAttempts,
+		Paused:               s.Paused(),
+		Polling:              s.Polling(),
+		Handoff:              s.HandoffState(),
+		Channels:             s.subscriptions.snapshot(),
+		Memory:               s.memory.Stats(),
+		HandleLatency:        s.latencies.stats(),
+	}
+
+	if s.pool != nil {
+		stats := s.pool.Stats()
+
+		status.Pool = RedisPoolStats{
+			ActiveCount:  stats.ActiveCount,
+			IdleCount:    stats.IdleCount,
+			WaitCount:    stats.WaitCount,
+			WaitDuration: stats.WaitDuration,
+		}
+	}
+
+	if s.dispatcher != nil {
+		status.Dispatch = s.dispatcher.Stats()
+	}
+
+	return status
+}
+
+func (s *RedisSubscriber) collectStats() {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(redisStatsInterval)
+	defer ticker.Stop()
+
+	// The pool only reports the total wait count, so the counter is updated with deltas
+	var waits int64
+
+	for {
+		select {
+		case <-s.shutdownCh:
+			return
+		case <-ticker.C:
+			status := s.Status()
+
+			s.metrics.GaugeSet(metricsRedisPoolActive, uint64(status.Pool.ActiveCount))
+			s.metrics.GaugeSet(metricsRedisPoolIdle, uint64(status.Pool.IdleCount))
+			if status.Pool.WaitCount > waits {
+				s.metrics.CounterAdd(metricsRedisPoolWaits, uint64(status.Pool.WaitCount-waits))
+				waits = status.Pool.WaitCount
+			}
+
+			s.checkMemory(status.Memory)
+			s.checkLatency(status.HandleLatency)
+			s.emitReceivedEvent()
+		}
+	}
+}
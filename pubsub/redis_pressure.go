@@ -0,0 +1,50 @@
+package pubsub
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// How long to hold each message (thus, reading from Redis) while the node is under pressure (block policy)
+	redisNodePressureDelay = 10 * time.Millisecond
+
+	metricsRedisNodePressure = "redis_node_pressure_total"
+)
+
+// nodeUnderPressure returns true if the node reports the pressure above the configured threshold.
+// State changes are logged.
+func (s *RedisSubscriber) nodeUnderPressure() bool {
+	if s.nodePressureThreshold <= 0 {
+		return false
+	}
+
+	reporter, ok := s.currentHandler().(PressureReporter)
+
+	if !ok {
+		return false
+	}
+
+	pressure := reporter.Pressure()
+	under := pressure >= s.nodePressureThreshold
+
+	var state int32
+
+	if under {
+		state = 1
+	}
+
+	if atomic.SwapInt32(&s.nodePressured, state) != state {
+		if under {
+			s.logger().Warnf("Node is under pressure (%.2f), applying the %s dispatch policy", pressure, s.dispatchPolicy)
+		} else {
+			s.logger().Infof("Node pressure is relieved (%.2f)", pressure)
+		}
+	}
+
+	if under {
+		s.metrics.CounterIncrement(metricsRedisNodePressure)
+	}
+
+	return under
+}
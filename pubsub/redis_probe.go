@@ -0,0 +1,99 @@
+package pubsub
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+)
+
+const redisProbeTokenPrefix = "anycable-probe:"
+
+var errRedisProbeConnectionLost = errors.New("Redis subscription connection lost before the probe reply") //nolint:stylecheck
+
+// redisProbes tracks live healthcheck probes waiting for their PONG replies.
+// Each probe has a unique token (sent as the PING payload), so concurrent probes and keepalive pings
+// (which have no payload) never resolve each other.
+type redisProbes struct {
+	mu      sync.Mutex
+	seq     uint64
+	pending map[string]chan error
+}
+
+func (p *redisProbes) add() (string, chan error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.pending == nil {
+		p.pending = make(map[string]chan error)
+	}
+
+	p.seq++
+	token := redisProbeTokenPrefix + strconv.FormatUint(p.seq, 10)
+	result := make(chan error, 1)
+
+	p.pending[token] = result
+
+	return token, result
+}
+
+func (p *redisProbes) remove(token string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	delete(p.pending, token)
+}
+
+// resolve completes the probe with the token (if it's still waiting)
+func (p *redisProbes) resolve(token string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if result, ok := p.pending[token]; ok {
+		result <- nil
+		delete(p.pending, token)
+	}
+}
+
+// failAll completes all the waiting probes with the error (e.g., when the connection is closed,
+// so the replies never arrive)
+func (p *redisProbes) failAll(err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for token, result := range p.pending {
+		result <- err
+		delete(p.pending, token)
+	}
+}
+
+// HealthcheckLive verifies that the subscription connection is alive by sending a PING with a unique token
+// over it and waiting for the matching PONG (until the context is done).
+// Unlike Healthcheck, it doesn't open extra connections.
+//
+// The check fails if the subscriber is not connected or delivery is paused in the block mode
+// (replies are not read). It's not supported in the subscribe-noop keepalive mode, since PING
+// is likely not allowed in subscribed mode in this case.
+func (s *RedisSubscriber) HealthcheckLive(ctx context.Context) error {
+	if s.keepaliveMode == redisKeepaliveSubscribeNoop {
+		return fmt.Errorf("live healthcheck is not supported in the %s keepalive mode", redisKeepaliveSubscribeNoop)
+	}
+
+	token, result := s.probes.add()
+	defer s.probes.remove(token)
+
+	// PING is sent by the serve loop, since it owns writes to the subscription connection
+	select {
+	case s.probeCh <- token:
+	case <-ctx.Done():
+		return fmt.Errorf("Redis subscription is not connected: %w", ctx.Err()) //nolint:stylecheck
+	}
+
+	select {
+	case err := <-result:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,51 @@
+package pubsub
+
+import (
+	"context"
+	"runtime/pprof"
+)
+
+const (
+	// pprof labels attached to the subscriber goroutines, so profiles attribute time to pub/sub work
+	redisProfilingBackendLabel = "pubsub"
+	redisProfilingRoutineLabel = "pubsub_routine"
+	redisProfilingChannelLabel = "pubsub_channel"
+
+	// The max number of per-channel label sets cached by a dispatch worker
+	redisProfilingChannelsLimit = 1000
+)
+
+func redisProfilingLabels(routine string, channel string) pprof.LabelSet {
+	return pprof.Labels(
+		redisProfilingBackendLabel, "redis",
+		redisProfilingRoutineLabel, routine,
+		redisProfilingChannelLabel, channel,
+	)
+}
+
+// redisChannelLabels caches per-channel profiling contexts of a goroutine processing messages from different channels,
+// so switching labels doesn't allocate on every message
+type redisChannelLabels struct {
+	routine  string
+	contexts map[string]context.Context
+}
+
+func newRedisChannelLabels(routine string) *redisChannelLabels {
+	return &redisChannelLabels{routine: routine, contexts: make(map[string]context.Context)}
+}
+
+// apply sets the current goroutine labels for the channel
+func (l *redisChannelLabels) apply(channel string) {
+	ctx, ok := l.contexts[channel]
+
+	if !ok {
+		if len(l.contexts) >= redisProfilingChannelsLimit {
+			l.contexts = make(map[string]context.Context)
+		}
+
+		ctx = pprof.WithLabels(context.Background(), redisProfilingLabels(l.routine, channel))
+		l.contexts[channel] = ctx
+	}
+
+	pprof.SetGoroutineLabels(ctx)
+}
@@ -0,0 +1,47 @@
+package pubsub
+
+import (
+	"time"
+
+	"github.com/FZambia/sentinel"
+	"github.com/gomodule/redigo/redis"
+)
+
+const (
+	defaultRedisSentinelPromotionWait = 1000
+	// How often to re-check the role of the resolved master while waiting for its promotion
+	redisPromotionCheckInterval = 100 * time.Millisecond
+)
+
+// testRole checks the role of the server the connection is established to.
+// Right after a failover, sentinels may already report the new master while it's still finishing the promotion
+// (and reports itself as a replica), so the role check is retried on the same connection
+// for up to the configured promotion wait time before abandoning the address.
+func (s *RedisSubscriber) testRole(c redis.Conn, role string) bool {
+	if sentinel.TestRole(c, role) {
+		return true
+	}
+
+	if role != redisRoleMaster || s.promotionWait <= 0 {
+		return false
+	}
+
+	checks := int(s.promotionWait / redisPromotionCheckInterval)
+
+	s.logger().Debugf("Resolved Redis master is not promoted yet, waiting for up to %s", s.promotionWait)
+
+	for i := 0; i < checks; i++ {
+		select {
+		case <-s.shutdownCh:
+			return false
+		case <-s.clock.after(redisPromotionCheckInterval):
+		}
+
+		if sentinel.TestRole(c, role) {
+			s.logger().Infof("Resolved Redis master has been promoted after %s", time.Duration(i+1)*redisPromotionCheckInterval)
+			return true
+		}
+	}
+
+	return false
+}
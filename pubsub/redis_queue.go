@@ -0,0 +1,187 @@
+package pubsub
+
+import (
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/anycable/anycable-go/metrics"
+	"github.com/anycable/anycable-go/pubsub/logging"
+	"github.com/gomodule/redigo/redis"
+)
+
+const (
+	defaultRedisQueueKey = "__anycable_queue__"
+	// BLPOP timeout (seconds); defines how fast we react on shutdown
+	redisQueuePopTimeout = 1
+	// Client name suffix for queue connections
+	redisClientNameQueue = "queue"
+
+	metricsRedisQueueMsg = "redis_queue_msg_total"
+)
+
+// RedisQueueSubscriber consumes broadcasts from a Redis list via BLPOP.
+//
+// Unlike pub/sub, messages are persisted in the list, so nothing is lost during short disconnects.
+// Every message is delivered to exactly one consumer, thus, this mode is only suitable for a single-node setup
+// (or when each node has its own list).
+//
+// Connection settings (URL validation, TLS, timeouts, client names, backoff and logging)
+// are shared with RedisSubscriber.
+type RedisQueueSubscriber struct {
+	// The pub/sub subscriber is only used to validate the configuration and to dial connections (it's never started)
+	base             *RedisSubscriber
+	node             Handler
+	metrics          metrics.Instrumenter
+	key              string
+	reconnectAttempt int
+	shutdownCh       chan struct{}
+	shutdownOnce     sync.Once
+	wg               sync.WaitGroup
+	log              logging.Logger
+}
+
+var _ Subscriber = (*RedisQueueSubscriber)(nil)
+
+// NewRedisQueueSubscriber returns new RedisQueueSubscriber struct (metrics could be nil to disable metrics)
+func NewRedisQueueSubscriber(node Handler, metrics metrics.Instrumenter, config *RedisConfig) *RedisQueueSubscriber {
+	metrics = instrumenterOrNoop(metrics)
+
+	registerCounter(metrics, config.MetricsTags, metricsRedisQueueMsg, "The total number of messages received from Redis queue")
+
+	base := NewRedisSubscriber(node, nil, config)
+
+	return &RedisQueueSubscriber{
+		base:       base,
+		node:       node,
+		metrics:    metrics,
+		key:        config.QueueKey,
+		shutdownCh: make(chan struct{}),
+		log:        base.log.WithField("provider", "redis_queue"),
+	}
+}
+
+// Start validates the configuration and starts consuming messages in the background
+func (s *RedisQueueSubscriber) Start(done chan (error)) error {
+	redisURL, err := url.Parse(s.base.url)
+
+	if err != nil {
+		return err
+	}
+
+	if err = checkRedisScheme(redisURL); err != nil {
+		return err
+	}
+
+	if err = s.base.checkTLS(redisURL); err != nil {
+		return err
+	}
+
+	if s.base.localAddr != "" {
+		if s.base.localTCPAddr, err = resolveLocalAddr(s.base.localAddr); err != nil {
+			return err
+		}
+	}
+
+	s.log.Infof("Consuming broadcasts from Redis list: %s", s.key)
+
+	s.wg.Add(1)
+	go s.run(done)
+
+	return nil
+}
+
+// Shutdown stops consuming messages and waits for the current message to be processed
+func (s *RedisQueueSubscriber) Shutdown() error {
+	s.shutdownOnce.Do(func() { close(s.shutdownCh) })
+	s.wg.Wait()
+
+	return nil
+}
+
+func (s *RedisQueueSubscriber) run(done chan (error)) {
+	defer s.wg.Done()
+
+	for {
+		if err := s.consume(); err != nil {
+			s.log.Warnf("Redis connection failed: %v", err)
+		}
+
+		if s.stopped() {
+			return
+		}
+
+		s.reconnectAttempt++
+
+		if s.reconnectAttempt >= maxReconnectAttempts {
+			done <- ErrReconnectExceeded
+			return
+		}
+
+		delay := nextRetryWithRand(s.reconnectAttempt, s.base.clock.intn)
+
+		if delay > 0 {
+			s.log.Infof("Next Redis reconnect attempt in %s", delay)
+
+			select {
+			case <-s.shutdownCh:
+				return
+			case <-s.base.clock.after(delay):
+			}
+		}
+	}
+}
+
+func (s *RedisQueueSubscriber) consume() error {
+	dialOptions := append(s.base.dialOptions(), s.base.clientNameOptions(redisClientNameQueue)...)
+
+	c, err := redis.DialURL(s.base.url, dialOptions...)
+
+	if err != nil {
+		return err
+	}
+
+	defer c.Close()
+
+	if _, err = c.Do("PING"); err != nil {
+		return err
+	}
+
+	connectedAt := time.Now()
+
+	for !s.stopped() {
+		// Only reset reconnect attempts when the connection is stable (see RedisSubscriber.listen)
+		if s.reconnectAttempt > 0 && time.Since(connectedAt) >= redisStableConnectionDuration {
+			s.reconnectAttempt = 0
+		}
+
+		reply, err := redis.ByteSlices(c.Do("BLPOP", s.key, redisQueuePopTimeout))
+
+		if err == redis.ErrNil {
+			continue
+		}
+
+		if err != nil {
+			return err
+		}
+
+		// Reply contains the key name and the value
+		if len(reply) != 2 {
+			continue
+		}
+
+		s.metrics.CounterIncrement(metricsRedisQueueMsg)
+		s.node.HandlePubSub(reply[1])
+	}
+
+	return nil
+}
+
+func (s *RedisQueueSubscriber) stopped() bool {
+	select {
+	case <-s.shutdownCh:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,102 @@
+package pubsub
+
+import (
+	"testing"
+	"time"
+
+	"github.com/anycable/anycable-go/metrics"
+	"github.com/anycable/anycable-go/mocks"
+	"github.com/anycable/anycable-go/pubsub/logging"
+	"github.com/apex/log"
+	"github.com/apex/log/handlers/memory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewRedisQueueSubscriber(t *testing.T) {
+	config := NewRedisConfig()
+
+	subscriber, err := NewSubscriber(&mocks.Handler{}, metrics.NoopMetrics{}, "redis_queue", &config, nil, nil)
+	require.NoError(t, err)
+
+	queue, ok := subscriber.(*RedisQueueSubscriber)
+	require.True(t, ok)
+
+	assert.Equal(t, "__anycable_queue__", queue.key)
+	assert.NoError(t, queue.Shutdown())
+}
+
+func TestRedisQueueSubscriberStart(t *testing.T) {
+	t.Run("With unsupported URL scheme", func(t *testing.T) {
+		config := NewRedisConfig()
+		config.URL = "foo://localhost:6379"
+
+		subscriber := NewRedisQueueSubscriber(&mocks.Handler{}, metrics.NoopMetrics{}, &config)
+
+		assert.Error(t, subscriber.Start(make(chan error)))
+	})
+
+	t.Run("With strict TLS and unencrypted URL", func(t *testing.T) {
+		config := NewRedisConfig()
+		config.URL = "redis://localhost:6379"
+		config.TLSStrict = true
+
+		subscriber := NewRedisQueueSubscriber(&mocks.Handler{}, metrics.NoopMetrics{}, &config)
+
+		assert.Error(t, subscriber.Start(make(chan error)))
+	})
+}
+
+func TestRedisQueueSubscriberReconnect(t *testing.T) {
+	config := NewRedisConfig()
+	// Nothing listens on this port, so every connection attempt fails
+	config.URL = "redis://127.0.0.1:1"
+
+	subscriber := NewRedisQueueSubscriber(&mocks.Handler{}, metrics.NoopMetrics{}, &config)
+
+	var delays []time.Duration
+
+	subscriber.base.clock = redisClock{
+		after: func(d time.Duration) <-chan time.Time {
+			delays = append(delays, d)
+
+			ch := make(chan time.Time, 1)
+			ch <- time.Now()
+			return ch
+		},
+		intn: func(n int) int { return 0 },
+	}
+
+	done := make(chan error, 1)
+
+	require.NoError(t, subscriber.Start(done))
+
+	select {
+	case err := <-done:
+		assert.Equal(t, ErrReconnectExceeded, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("Timed out waiting for the subscriber to give up")
+	}
+
+	assert.NoError(t, subscriber.Shutdown())
+	assert.Equal(t, []time.Duration{time.Second, 4 * time.Second, 9 * time.Second}, delays)
+}
+
+func TestRedisQueueSubscriberLogger(t *testing.T) {
+	handler := memory.New()
+	custom := &log.Logger{Handler: handler, Level: log.DebugLevel}
+
+	config := NewRedisConfig()
+	config.Logger = logging.NewApexLogger(log.NewEntry(custom)).WithField("app", "test")
+
+	subscriber := NewRedisQueueSubscriber(&mocks.Handler{}, metrics.NoopMetrics{}, &config)
+	subscriber.log.Warnf("Redis is %s", "down")
+
+	require.Len(t, handler.Entries, 1)
+
+	entry := handler.Entries[0]
+	assert.Equal(t, "Redis is down", entry.Message)
+	assert.Equal(t, "test", entry.Fields["app"])
+	assert.Equal(t, "pubsub", entry.Fields["context"])
+	assert.Equal(t, "redis_queue", entry.Fields["provider"])
+}
@@ -0,0 +1,185 @@
+package pubsub
+
+import (
+	"sync"
+	"time"
+
+	"github.com/anycable/anycable-go/metrics"
+	"github.com/anycable/anycable-go/pubsub/logging"
+	"github.com/gomodule/redigo/redis"
+)
+
+const (
+	defaultRedisReplaySize        = 100
+	defaultRedisReplayStagingSize = 1000
+
+	metricsRedisReplayedMsg = "redis_replayed_msg_total"
+)
+
+// redisReplayConfig contains the recent broadcasts replay settings
+type redisReplayConfig struct {
+	// Redis list with recent broadcasts maintained by the broadcaster
+	Key string
+	// The max number of recent broadcasts to replay
+	Size int
+	// Replay after subscribing and stage live broadcasts until the replay is completed
+	Ordered bool
+	// The max number of live broadcasts to stage
+	StagingSize int
+	// Broadcasts channel (messages from other channels are never staged)
+	Channel string
+	// Timeout for reading the list
+	CommandTimeout time.Duration
+}
+
+// redisReplayer reads the recent broadcasts from the replay list and delivers them.
+// The broadcaster is expected to maintain the list oldest-first (RPUSH + LTRIM key -N -1).
+//
+// By default, replay happens on every (re)connect before subscribing to the channel, so replayed messages
+// are always delivered before live ones. Broadcasts published between reading the list and subscribing
+// (a single round-trip) could be missed; broadcasts already delivered before the reconnect are replayed again
+// (enable deduplication or envelopes to skip them).
+//
+// In the ordered mode, replay happens right after the subscription is confirmed (so nothing is missed),
+// and live broadcasts are staged until the replay is completed.
+type redisReplayer struct {
+	config  redisReplayConfig
+	deliver func(channel string, data []byte)
+	memory  *redisMemory
+	metrics metrics.Instrumenter
+	logger  func() logging.Logger
+
+	mu       sync.Mutex
+	gen      uint64
+	staging  bool
+	pending  bool
+	messages []redisMessage
+}
+
+func newRedisReplayer(config redisReplayConfig, deliver func(channel string, data []byte), metrics metrics.Instrumenter, logger func() logging.Logger) *redisReplayer {
+	return &redisReplayer{config: config, deliver: deliver, metrics: metrics, logger: logger}
+}
+
+// SetMemory sets the memory tracker to account staged messages
+func (r *redisReplayer) SetMemory(memory *redisMemory) {
+	r.memory = memory
+}
+
+// Enabled returns true if the replay list is configured
+func (r *redisReplayer) Enabled() bool {
+	return r.config.Key != "" && r.config.Size > 0
+}
+
+// Ordered returns true if the replay happens after subscribing (with live broadcasts staged)
+func (r *redisReplayer) Ordered() bool {
+	return r.Enabled() && r.config.Ordered
+}
+
+// Replay reads the recent broadcasts via the connection and delivers them
+func (r *redisReplayer) Replay(c redis.Conn) {
+	if !r.Enabled() {
+		return
+	}
+
+	messages, err := redis.ByteSlices(redis.DoWithTimeout(c, r.config.CommandTimeout, "LRANGE", r.config.Key, -r.config.Size, -1))
+
+	if err != nil {
+		r.logger().Warnf("Failed to read recent broadcasts from Redis list %s: %v", r.config.Key, err)
+		return
+	}
+
+	for _, data := range messages {
+		r.metrics.CounterIncrement(metricsRedisReplayedMsg)
+		r.deliver(r.config.Channel, data)
+	}
+
+	r.logger().Debugf("Replayed %d recent broadcasts from Redis list %s", len(messages), r.config.Key)
+}
+
+// StartStaging makes live broadcasts to be staged until the replay for the returned generation is completed
+func (r *redisReplayer) StartStaging() uint64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.gen++
+	r.staging = true
+	r.pending = true
+
+	return r.gen
+}
+
+// TakePending returns the generation to replay if the replay hasn't been started yet
+func (r *redisReplayer) TakePending() (uint64, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !r.pending {
+		return 0, false
+	}
+
+	r.pending = false
+
+	return r.gen, true
+}
+
+// Stage puts the live broadcast into the staging buffer and returns true if the replay is in progress.
+// If the buffer is full, staged messages are flushed and the rest are delivered right away (best-effort ordering).
+func (r *redisReplayer) Stage(channel string, data []byte) bool {
+	if channel != r.config.Channel {
+		return false
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !r.staging {
+		return false
+	}
+
+	if len(r.messages) >= r.config.StagingSize || r.memory.pressure() {
+		r.logger().Warnf("Redis replay takes too long (%d live messages staged), delivering live messages without waiting for the replay; ordering is not guaranteed", len(r.messages))
+		r.flushLocked()
+		return false
+	}
+
+	r.messages = append(r.messages, redisMessage{channel: channel, data: data})
+	r.memory.reserve(messageSize(channel, data))
+
+	return true
+}
+
+// Flush delivers the staged messages and stops staging (unless a newer generation has been started)
+func (r *redisReplayer) Flush(gen uint64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if gen != r.gen {
+		return
+	}
+
+	r.flushLocked()
+}
+
+func (r *redisReplayer) flushLocked() {
+	for _, msg := range r.messages {
+		r.memory.release(messageSize(msg.channel, msg.data))
+		r.deliver(msg.channel, msg.data)
+	}
+
+	r.messages = nil
+	r.staging = false
+	r.pending = false
+}
+
+// replayStaged replays the recent broadcasts via an auxiliary connection and then delivers the staged live ones.
+// It's called when the subscription to the broadcasts channel is confirmed, so there is no gap between replayed and live messages
+// (though there could be duplicates).
+func (s *RedisSubscriber) replayStaged(gen uint64) {
+	defer s.wg.Done()
+	defer s.replayer.Flush(gen)
+
+	c := s.pool.Get()
+	defer c.Close()
+
+	s.replayer.Replay(c)
+}
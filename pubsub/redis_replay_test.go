@@ -0,0 +1,144 @@
+package pubsub
+
+import (
+	"testing"
+
+	"github.com/anycable/anycable-go/metrics"
+	"github.com/anycable/anycable-go/mocks"
+	"github.com/gomodule/redigo/redis"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestRedisReplayer(config redisReplayConfig) (*redisReplayer, *[]string) {
+	var received []string
+
+	replayer := newRedisReplayer(config, func(channel string, data []byte) {
+		received = append(received, string(data))
+	}, metrics.NoopMetrics{}, testRedisLogger())
+
+	return replayer, &received
+}
+
+func TestRedisReplay(t *testing.T) {
+	replayer, received := newTestRedisReplayer(redisReplayConfig{Key: "__anycable_replay__", Size: 2, Channel: "__anycable__"})
+
+	assert.True(t, replayer.Enabled())
+	assert.False(t, replayer.Ordered())
+
+	conn := &listRedisConn{items: [][]byte{[]byte("first"), []byte("second")}}
+
+	replayer.Replay(conn)
+
+	assert.Equal(t, []interface{}{"__anycable_replay__", -2, -1}, conn.args)
+	assert.Equal(t, []string{"first", "second"}, *received)
+}
+
+func TestRedisReplayOrdered(t *testing.T) {
+	setup := func(stagingSize int) (*redisReplayer, *[]string) {
+		replayer, received := newTestRedisReplayer(redisReplayConfig{
+			Key:         "__anycable_replay__",
+			Size:        defaultRedisReplaySize,
+			Ordered:     true,
+			StagingSize: stagingSize,
+			Channel:     "__anycable__",
+		})
+
+		replayer.SetMemory(newRedisMemory(0))
+
+		return replayer, received
+	}
+
+	t.Run("Holds live messages until replay is completed", func(t *testing.T) {
+		replayer, received := setup(10)
+
+		assert.True(t, replayer.Ordered())
+
+		replayer.StartStaging()
+
+		assert.True(t, replayer.Stage("__anycable__", []byte("live_1")))
+		assert.False(t, replayer.Stage("__anycable_internal__", []byte("command")))
+		assert.Empty(t, *received)
+
+		gen, ok := replayer.TakePending()
+		require.True(t, ok)
+
+		_, ok = replayer.TakePending()
+		assert.False(t, ok)
+
+		replayer.Replay(&listRedisConn{items: [][]byte{[]byte("replayed_1"), []byte("replayed_2")}})
+		replayer.Flush(gen)
+
+		assert.Equal(t, []string{"replayed_1", "replayed_2", "live_1"}, *received)
+		assert.False(t, replayer.Stage("__anycable__", []byte("live_2")))
+		assert.Equal(t, int64(0), replayer.memory.Stats().Used)
+	})
+
+	t.Run("Falls back to best-effort ordering when staging buffer is full", func(t *testing.T) {
+		replayer, received := setup(2)
+
+		replayer.StartStaging()
+
+		assert.True(t, replayer.Stage("__anycable__", []byte("live_1")))
+		assert.True(t, replayer.Stage("__anycable__", []byte("live_2")))
+		assert.False(t, replayer.Stage("__anycable__", []byte("live_3")))
+
+		assert.Equal(t, []string{"live_1", "live_2"}, *received)
+		assert.False(t, replayer.Stage("__anycable__", []byte("live_4")))
+	})
+
+	t.Run("Ignores stale generations", func(t *testing.T) {
+		replayer, received := setup(10)
+
+		stale := replayer.StartStaging()
+		replayer.StartStaging()
+
+		assert.True(t, replayer.Stage("__anycable__", []byte("live_1")))
+
+		replayer.Flush(stale)
+
+		assert.Empty(t, *received)
+	})
+}
+
+func TestRedisReplayDisabled(t *testing.T) {
+	replayer, received := newTestRedisReplayer(redisReplayConfig{Channel: "__anycable__"})
+
+	assert.False(t, replayer.Enabled())
+	assert.False(t, replayer.Ordered())
+
+	conn := &listRedisConn{items: [][]byte{[]byte("first")}}
+
+	replayer.Replay(conn)
+
+	assert.Nil(t, conn.args)
+	assert.Empty(t, *received)
+}
+
+func TestRedisReplayStaged(t *testing.T) {
+	config := NewRedisConfig()
+	config.ReplayKey = "__anycable_replay__"
+	config.ReplayOrdered = true
+
+	handler := &mocks.Handler{}
+	subscriber := NewRedisSubscriber(handler, metrics.NoopMetrics{}, &config)
+
+	var received []string
+
+	handler.On("HandlePubSub", mock.Anything).Run(func(args mock.Arguments) {
+		received = append(received, string(args.Get(0).([]byte)))
+	})
+
+	conn := &listRedisConn{items: [][]byte{[]byte("replayed")}}
+	subscriber.pool = &redis.Pool{Dial: func() (redis.Conn, error) { return conn, nil }}
+
+	gen := subscriber.replayer.StartStaging()
+
+	subscriber.InjectBroadcast("__anycable__", []byte("live"))
+
+	subscriber.wg.Add(1)
+	subscriber.replayStaged(gen)
+
+	assert.Equal(t, []string{"replayed", "live"}, received)
+}
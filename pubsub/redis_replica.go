@@ -0,0 +1,57 @@
+package pubsub
+
+import (
+	"math/rand"
+)
+
+const (
+	redisRoleMaster  = "master"
+	redisRoleReplica = "slave"
+)
+
+// resolveSentinelReplica picks a random replica known to sentinels to subscribe to.
+// Falls back to the master if there are no replicas (or they couldn't be resolved).
+func (s *RedisSubscriber) resolveSentinelReplica() {
+	s.setReplicaAddr("")
+
+	addrs, err := s.sentinelClient.SlaveAddrs()
+
+	if err != nil {
+		s.logger().Warnf("Failed to get replica addresses from sentinel, subscribing to master: %v", err)
+		return
+	}
+
+	if len(addrs) == 0 {
+		s.logger().Warn("No replicas found by sentinel, subscribing to master")
+		return
+	}
+
+	addr := addrs[rand.Intn(len(addrs))] // #nosec
+
+	s.logger().Debugf("Got replica address from sentinel: %s", addr)
+
+	s.setReplicaAddr(addr)
+}
+
+// subscriptionTarget returns the URL to subscribe to and the expected role of the server.
+// Only pub/sub connections go to replicas; the auxiliary pool always uses the master.
+func (s *RedisSubscriber) subscriptionTarget() (string, string) {
+	s.urlMu.RLock()
+	defer s.urlMu.RUnlock()
+
+	if s.replicaAddr == "" {
+		return s.url, redisRoleMaster
+	}
+
+	uri := *s.uri
+	uri.Host = s.replicaAddr
+
+	return uri.String(), redisRoleReplica
+}
+
+func (s *RedisSubscriber) setReplicaAddr(addr string) {
+	s.urlMu.Lock()
+	defer s.urlMu.Unlock()
+
+	s.replicaAddr = addr
+}
@@ -0,0 +1,108 @@
+package pubsub
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+	nanoid "github.com/matoous/go-nanoid"
+)
+
+const (
+	redisRoundTripChannelPrefix = "__anycable_roundtrip__"
+)
+
+// RoundTrip checks that the whole pub/sub chain works against the configured Redis:
+// it subscribes to a temporary unique channel, publishes a message to it and waits for the message to come back
+// (or for the context to be done).
+// The same connection settings (TLS, sentinels, auth) as for the main subscription are used.
+// The message is not passed to the node.
+func (s *RedisSubscriber) RoundTrip(ctx context.Context) error {
+	id, err := nanoid.Nanoid()
+
+	if err != nil {
+		return err
+	}
+
+	channel := redisRoundTripChannelPrefix + id
+	payload := []byte(id)
+
+	c, err := redis.DialURLContext(ctx, s.currentURL(), s.dialOptions()...)
+
+	if err != nil {
+		return fmt.Errorf("failed to connect to Redis: %w", err)
+	}
+
+	psc := redis.PubSubConn{Conn: c}
+	defer psc.Close()
+
+	if err = psc.Subscribe(channel); err != nil {
+		return fmt.Errorf("failed to subscribe to Redis channel: %w", err)
+	}
+
+	for {
+		switch v := psc.ReceiveWithTimeout(receiveTimeout(ctx)).(type) {
+		case redis.Subscription:
+			if v.Kind != "subscribe" || v.Channel != channel {
+				continue
+			}
+
+			if err = s.roundTripPublish(ctx, channel, payload); err != nil {
+				return fmt.Errorf("failed to publish to Redis channel: %w", err)
+			}
+		case redis.Message:
+			if v.Channel != channel || !bytes.Equal(v.Data, payload) {
+				continue
+			}
+
+			return psc.Unsubscribe(channel)
+		case error:
+			if ctx.Err() != nil {
+				return errors.New("timed out waiting for the round trip message")
+			}
+
+			return v
+		}
+	}
+}
+
+func (s *RedisSubscriber) roundTripPublish(ctx context.Context, channel string, payload []byte) error {
+	var c redis.Conn
+	var err error
+
+	if s.pool != nil {
+		c, err = s.pool.GetContext(ctx)
+	} else {
+		c, err = redis.DialURLContext(ctx, s.currentURL(), s.dialOptions()...)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	defer c.Close()
+
+	_, err = redis.DoContext(c, ctx, "PUBLISH", channel, payload)
+
+	return err
+}
+
+// receiveTimeout returns the time left until the context deadline
+// (zero means no timeout, i.e., blocking receive)
+func receiveTimeout(ctx context.Context) time.Duration {
+	deadline, ok := ctx.Deadline()
+
+	if !ok {
+		return 0
+	}
+
+	if left := time.Until(deadline); left > 0 {
+		return left
+	}
+
+	// Non-zero value to avoid blocking forever when the deadline has already passed
+	return time.Nanosecond
+}
@@ -0,0 +1,69 @@
+package pubsub
+
+import (
+	"fmt"
+	"sync/atomic"
+	"time"
+)
+
+const defaultRedisLogSampleMaxSize = 256
+
+// redisSampler decides which received messages to log: every N-th message and/or
+// at most one message per interval (whichever comes first)
+type redisSampler struct {
+	every    uint64
+	interval time.Duration
+	maxSize  int
+	count    uint64
+	last     int64
+}
+
+// newRedisSampler returns a sampler or nil if sampling is disabled
+func newRedisSampler(every int, interval time.Duration, maxSize int) *redisSampler {
+	if every <= 0 && interval <= 0 {
+		return nil
+	}
+
+	if every < 0 {
+		every = 0
+	}
+
+	return &redisSampler{every: uint64(every), interval: interval, maxSize: maxSize}
+}
+
+func (p *redisSampler) sample(now time.Time) bool {
+	if p.every > 0 && atomic.AddUint64(&p.count, 1)%p.every == 0 {
+		atomic.StoreInt64(&p.last, now.UnixNano())
+		return true
+	}
+
+	if p.interval <= 0 {
+		return false
+	}
+
+	last := atomic.LoadInt64(&p.last)
+
+	if now.UnixNano()-last < int64(p.interval) {
+		return false
+	}
+
+	return atomic.CompareAndSwapInt64(&p.last, last, now.UnixNano())
+}
+
+// truncate returns the payload truncated to the max size (with the original size noted)
+func (p *redisSampler) truncate(data []byte) string {
+	if p.maxSize <= 0 || len(data) <= p.maxSize {
+		return string(data)
+	}
+
+	return fmt.Sprintf("%s... (%d bytes total)", data[:p.maxSize], len(data))
+}
+
+// sampleMessage logs the received message at the info level if it's sampled (regardless of the debug mode)
+func (s *RedisSubscriber) sampleMessage(channel string, data []byte) {
+	if s.sampler == nil || !s.sampler.sample(time.Now()) {
+		return
+	}
+
+	s.messageLog(data).WithField("channel", channel).Infof("Sampled Redis message: %s", s.sampler.truncate(data))
+}
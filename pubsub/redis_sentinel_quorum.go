@@ -0,0 +1,62 @@
+package pubsub
+
+import (
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/gomodule/redigo/redis"
+)
+
+// checkSentinelQuorum verifies that at least the configured number of sentinels are reachable
+// and agree on the master address (to avoid connecting to a stale master during a sentinel split-brain)
+func (s *RedisSubscriber) checkSentinelQuorum(master string) error {
+	if s.sentinelMinReachable <= 0 {
+		return nil
+	}
+
+	addrs := strings.Split(s.sentinels, ",")
+	reachable := 0
+	agreeing := 0
+
+	for _, addr := range addrs {
+		c, err := s.sentinelClient.Dial(addr)
+
+		if err != nil {
+			continue
+		}
+
+		reachable++
+
+		if addr, err := querySentinelMaster(c, s.sentinelClient.MasterName); err == nil && addr == master {
+			agreeing++
+		}
+
+		c.Close()
+	}
+
+	s.logger().Debugf("Sentinels reachable: %d of %d, agreeing on master %s: %d (required: %d)", reachable, len(addrs), master, agreeing, s.sentinelMinReachable)
+
+	if agreeing < s.sentinelMinReachable {
+		return fmt.Errorf(
+			"sentinel quorum is not met: %d of %d sentinels reachable, %d agree on master %s (required: %d)",
+			reachable, len(addrs), agreeing, master, s.sentinelMinReachable,
+		)
+	}
+
+	return nil
+}
+
+func querySentinelMaster(c redis.Conn, masterName string) (string, error) {
+	res, err := redis.Strings(c.Do("SENTINEL", "get-master-addr-by-name", masterName))
+
+	if err != nil {
+		return "", err
+	}
+
+	if len(res) != 2 {
+		return "", fmt.Errorf("unexpected sentinel reply: %v", res)
+	}
+
+	return net.JoinHostPort(res[0], res[1]), nil
+}
@@ -0,0 +1,63 @@
+package pubsub
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+)
+
+const defaultRedisSentinelMaxAddrs = 16
+
+// parseSentinels validates the comma-separated list of sentinel addresses ([:password@]host:port)
+// and returns the addresses.
+// The number of addresses is limited by max (not limited if zero), so a malformed value
+// (e.g., accidentally concatenated strings) fails fast instead of making us dial lots of bogus addresses.
+func parseSentinels(raw string, max int) ([]string, error) {
+	addrs := strings.Split(raw, ",")
+
+	if max > 0 && len(addrs) > max {
+		return nil, fmt.Errorf("too many sentinel addresses: %d (max: %d)", len(addrs), max)
+	}
+
+	for i, addr := range addrs {
+		if err := validateSentinelAddr(addr); err != nil {
+			return nil, fmt.Errorf("invalid sentinel address #%d: %w", i+1, err)
+		}
+	}
+
+	return addrs, nil
+}
+
+func validateSentinelAddr(addr string) error {
+	if addr == "" {
+		return fmt.Errorf("address is empty")
+	}
+
+	if strings.ContainsAny(addr, " \t\r\n") {
+		return fmt.Errorf("address must not contain whitespace")
+	}
+
+	hostport := addr
+
+	// Strip the credentials (the password may contain any characters and must not be reported)
+	if i := strings.LastIndex(addr, "@"); i >= 0 {
+		hostport = addr[i+1:]
+	}
+
+	host, port, err := net.SplitHostPort(hostport)
+
+	if err != nil {
+		return fmt.Errorf("expected host:port, got %s", hostport)
+	}
+
+	if host == "" {
+		return fmt.Errorf("host is missing: %s", hostport)
+	}
+
+	if p, err := strconv.Atoi(port); err != nil || p <= 0 || p > 65535 {
+		return fmt.Errorf("invalid port: %s", hostport)
+	}
+
+	return nil
+}
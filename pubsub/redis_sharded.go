@@ -0,0 +1,191 @@
+package pubsub
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/gomodule/redigo/redis"
+)
+
+const (
+	redisClusterSlots = 16384
+)
+
+// errRedisShardMoved is reported when Redis unsubscribes us from a sharded channel
+// (e.g., the channel's slot has been migrated to another node)
+var errRedisShardMoved = errors.New("Redis sharded channel has been moved") //nolint:stylecheck
+
+// redisClusterSlot returns the cluster hash slot for the key (respecting hash tags, e.g., "{anycable}:broadcasts")
+func redisClusterSlot(key string) int {
+	if start := strings.IndexByte(key, '{'); start >= 0 {
+		if end := strings.IndexByte(key[start+1:], '}'); end > 0 {
+			key = key[start+1 : start+1+end]
+		}
+	}
+
+	return int(crc16([]byte(key)) % redisClusterSlots)
+}
+
+// crc16 implements CRC16-CCITT (XMODEM) used by Redis Cluster for keys hashing
+func crc16(data []byte) uint16 {
+	var crc uint16
+
+	for _, b := range data {
+		crc ^= uint16(b) << 8
+
+		for i := 0; i < 8; i++ {
+			if crc&0x8000 != 0 {
+				crc = crc<<1 ^ 0x1021
+			} else {
+				crc <<= 1
+			}
+		}
+	}
+
+	return crc
+}
+
+// checkSlot returns an error if any of the channels doesn't belong to the broadcasts channel's hash slot in sharded mode
+// (all the channels are subscribed to via the connection to the shard owning the broadcasts channel)
+func (s *RedisSubscriber) checkSlot(channels ...string) error {
+	if !s.sharded {
+		return nil
+	}
+
+	slot := redisClusterSlot(s.channel)
+
+	for _, channel := range channels {
+		if redisClusterSlot(channel) != slot {
+			return fmt.Errorf("channel %s doesn't belong to the same hash slot as %s in sharded mode (use hash tags, e.g., {anycable})", channel, s.channel)
+		}
+	}
+
+	return nil
+}
+
+// shardURL returns the URL of the cluster node owning the subscription channels
+func (s *RedisSubscriber) shardURL(baseURL string) (string, error) {
+	c, err := redis.DialURL(baseURL, s.dialOptions()...)
+
+	if err != nil {
+		return "", err
+	}
+
+	defer c.Close()
+
+	slots, err := redis.Values(c.Do("CLUSTER", "SLOTS"))
+
+	if err != nil {
+		return "", err
+	}
+
+	var addr string
+
+	for _, channel := range s.channels() {
+		channelAddr, err := findSlotAddr(slots, redisClusterSlot(channel))
+
+		if err != nil {
+			return "", err
+		}
+
+		if addr != "" && addr != channelAddr {
+			return "", fmt.Errorf("all the channels must belong to the same shard in sharded mode (use hash tags, e.g., {anycable}): %v", s.channels())
+		}
+
+		addr = channelAddr
+	}
+
+	uri, err := url.Parse(baseURL)
+
+	if err != nil {
+		return "", err
+	}
+
+	uri.Host = addr
+
+	return uri.String(), nil
+}
+
+// findSlotAddr finds the master address for the slot in the CLUSTER SLOTS reply:
+// [[start, end, [host, port, id], replicas...], ...]
+func findSlotAddr(slots []interface{}, slot int) (string, error) {
+	for _, raw := range slots {
+		info, err := redis.Values(raw, nil)
+
+		if err != nil || len(info) < 3 {
+			continue
+		}
+
+		start, _ := redis.Int(info[0], nil)
+		end, _ := redis.Int(info[1], nil)
+
+		if slot < start || slot > end {
+			continue
+		}
+
+		node, err := redis.Values(info[2], nil)
+
+		if err != nil || len(node) < 2 {
+			return "", fmt.Errorf("malformed CLUSTER SLOTS reply for slot %d", slot)
+		}
+
+		host, _ := redis.String(node[0], nil)
+		port, _ := redis.Int(node[1], nil)
+
+		return net.JoinHostPort(host, strconv.Itoa(port)), nil
+	}
+
+	return "", fmt.Errorf("no cluster node found for slot %d", slot)
+}
+
+// receiveSharded reads a reply from the sharded pubsub connection and converts it
+// to the same types as redis.PubSubConn.Receive returns
+// (the Redis client doesn't support sharded pub/sub notifications).
+func receiveSharded(psc redis.PubSubConn) interface{} {
+	reply, err := redis.Values(psc.Conn.Receive())
+
+	if err != nil {
+		return err
+	}
+
+	var kind string
+
+	reply, err = redis.Scan(reply, &kind)
+
+	if err != nil {
+		return err
+	}
+
+	switch kind {
+	case "smessage":
+		var msg redis.Message
+
+		if _, err := redis.Scan(reply, &msg.Channel, &msg.Data); err != nil {
+			return err
+		}
+
+		return msg
+	case "ssubscribe", "sunsubscribe":
+		sub := redis.Subscription{Kind: kind}
+
+		if _, err := redis.Scan(reply, &sub.Channel, &sub.Count); err != nil {
+			return err
+		}
+
+		return sub
+	case "pong":
+		var pong redis.Pong
+
+		if _, err := redis.Scan(reply, &pong.Data); err != nil {
+			return err
+		}
+
+		return pong
+	}
+
+	return fmt.Errorf("unknown sharded pubsub notification: %s", kind)
+}
@@ -0,0 +1,74 @@
+package pubsub
+
+import (
+	"io"
+	"testing"
+
+	"github.com/anycable/anycable-go/metrics"
+	"github.com/anycable/anycable-go/mocks"
+	"github.com/gomodule/redigo/redis"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRedisClusterSlot(t *testing.T) {
+	assert.Equal(t, uint16(0x31C3), crc16([]byte("123456789")))
+	assert.Equal(t, 12182, redisClusterSlot("foo"))
+	assert.Equal(t, redisClusterSlot("anycable"), redisClusterSlot("{anycable}:broadcasts"))
+	assert.Equal(t, redisClusterSlot("{anycable}:broadcasts"), redisClusterSlot("{anycable}:internal"))
+}
+
+func TestFindSlotAddr(t *testing.T) {
+	slots := []interface{}{
+		[]interface{}{int64(0), int64(8191), []interface{}{[]byte("10.0.0.1"), int64(6379), []byte("id1")}},
+		[]interface{}{int64(8192), int64(16383), []interface{}{[]byte("10.0.0.2"), int64(6380), []byte("id2")}},
+	}
+
+	addr, err := findSlotAddr(slots, 100)
+	require.NoError(t, err)
+	assert.Equal(t, "10.0.0.1:6379", addr)
+
+	addr, err = findSlotAddr(slots, 12182)
+	require.NoError(t, err)
+	assert.Equal(t, "10.0.0.2:6380", addr)
+
+	_, err = findSlotAddr(slots[:1], 12182)
+	assert.Error(t, err)
+}
+
+func TestRedisReceiveSharded(t *testing.T) {
+	config := NewRedisConfig()
+	config.Sharded = true
+
+	handler := &mocks.Handler{}
+	subscriber := NewRedisSubscriber(handler, metrics.NoopMetrics{}, &config)
+
+	handler.On("HandlePubSub", []byte("hello"))
+
+	conn := &fakeRedisConn{reply: []interface{}{[]byte("smessage"), []byte("__anycable__"), []byte("hello")}, limit: 2}
+	done := make(chan error, 1)
+
+	subscriber.receive(redis.PubSubConn{Conn: conn}, done)
+	<-done
+
+	handler.AssertNumberOfCalls(t, "HandlePubSub", 2)
+
+	// Channels unsubscribed deliberately are not tracked, so that's not a slot migration
+	conn = &fakeRedisConn{reply: []interface{}{[]byte("sunsubscribe"), []byte("__anycable__"), int64(1)}, limit: 1}
+	subscriber.receive(redis.PubSubConn{Conn: conn}, done)
+
+	assert.Equal(t, io.EOF, <-done)
+
+	require.NoError(t, subscriber.subscriptions.subscribe(redis.PubSubConn{Conn: &fakeRedisConn{}}, subscriber.channels()))
+
+	conn = &fakeRedisConn{reply: []interface{}{[]byte("sunsubscribe"), []byte("__anycable__"), int64(0)}, limit: 1}
+	subscriber.receive(redis.PubSubConn{Conn: conn}, done)
+
+	assert.Equal(t, errRedisShardMoved, <-done)
+
+	conn = &fakeRedisConn{}
+	require.NoError(t, subscriber.subscriptions.subscribe(redis.PubSubConn{Conn: conn}, subscriber.channels()))
+	require.NoError(t, subscriber.unsubscribeAll(redis.PubSubConn{Conn: conn}))
+
+	assert.Equal(t, []string{"SSUBSCRIBE", "SUNSUBSCRIBE"}, conn.sent)
+}
@@ -0,0 +1,26 @@
+package pubsub
+
+import (
+	"hash/fnv"
+	"os"
+	"time"
+)
+
+// reconnectStagger returns the node-specific extra delay for reconnect attempts within the window,
+// so a fleet of nodes reconnecting after a Redis outage is spread over the window instead of
+// hitting Redis all at once. The delay is derived from the key hash (hostname if empty),
+// thus, it's stable for the node and different nodes get different delays.
+func reconnectStagger(key string, window time.Duration) time.Duration {
+	if window < time.Millisecond {
+		return 0
+	}
+
+	if key == "" {
+		key, _ = os.Hostname()
+	}
+
+	h := fnv.New32a()
+	h.Write([]byte(key)) // nolint:errcheck
+
+	return time.Duration(h.Sum32()%uint32(window.Milliseconds())) * time.Millisecond
+}
@@ -0,0 +1,79 @@
+package pubsub
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/anycable/anycable-go/pubsub/logging"
+)
+
+// redisTotals accumulates the subscriber lifetime totals for the shutdown summary
+// (metrics could be rotated or not collected at all, so we can't rely on them)
+type redisTotals struct {
+	startedAt  time.Time
+	received   int64
+	handled    int64
+	duplicates int64
+	coalesced  int64
+	connects   int64
+
+	mu      sync.Mutex
+	dropped map[string]int64
+}
+
+func (t *redisTotals) drop(reason string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.dropped == nil {
+		t.dropped = make(map[string]int64)
+	}
+
+	t.dropped[reason]++
+}
+
+// fields returns the totals as log fields (dropped messages are reported in total and per reason)
+func (t *redisTotals) fields(now time.Time) logging.Fields {
+	fields := logging.Fields{
+		"uptime":     now.Sub(t.startedAt).Round(time.Second).String(),
+		"received":   atomic.LoadInt64(&t.received),
+		"handled":    atomic.LoadInt64(&t.handled),
+		"duplicates": atomic.LoadInt64(&t.duplicates),
+		"coalesced":  atomic.LoadInt64(&t.coalesced),
+		"reconnects": 0,
+	}
+
+	if connects := atomic.LoadInt64(&t.connects); connects > 1 {
+		fields["reconnects"] = connects - 1
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var dropped int64
+
+	for reason, count := range t.dropped {
+		fields["dropped_"+reason] = count
+		dropped += count
+	}
+
+	fields["dropped"] = dropped
+
+	return fields
+}
+
+// logSummary logs the subscriber lifetime totals once (if the subscriber has been started)
+func (s *RedisSubscriber) logSummary() {
+	if s.totals.startedAt.IsZero() {
+		return
+	}
+
+	s.summaryOnce.Do(func() {
+		s.logger().
+			WithFields(s.totals.fields(time.Now())).
+			WithField("backend", "redis").
+			WithField("endpoint", redactRedisURL(s.currentURL())).
+			Info("Redis subscriber summary")
+	})
+}
@@ -0,0 +1,218 @@
+package pubsub
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+
+	"github.com/gomodule/redigo/redis"
+)
+
+const (
+	// The max number of messages waiting to be read from a tap; messages are skipped if the reader is too slow
+	redisTapBufferSize = 100
+	// Client name suffix for tap connections
+	redisClientNameTap = "tap"
+)
+
+// redisTaps keeps the channels tapped via the subscription connection
+type redisTaps struct {
+	mu   sync.RWMutex
+	taps map[string]map[chan []byte]struct{}
+}
+
+func (t *redisTaps) add(channel string, ch chan []byte) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.taps == nil {
+		t.taps = make(map[string]map[chan []byte]struct{})
+	}
+
+	if t.taps[channel] == nil {
+		t.taps[channel] = make(map[chan []byte]struct{})
+	}
+
+	t.taps[channel][ch] = struct{}{}
+}
+
+// remove removes the tap and closes its channel (no messages are sent to it afterwards)
+func (t *redisTaps) remove(channel string, ch chan []byte) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	delete(t.taps[channel], ch)
+
+	if len(t.taps[channel]) == 0 {
+		delete(t.taps, channel)
+	}
+
+	close(ch)
+}
+
+func (t *redisTaps) channels() []string {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	channels := make([]string, 0, len(t.taps))
+
+	for channel := range t.taps {
+		channels = append(channels, channel)
+	}
+
+	sort.Strings(channels)
+
+	return channels
+}
+
+// send passes a copy of the message to the channel taps without blocking; returns false if the channel is not tapped
+func (t *redisTaps) send(channel string, data []byte) bool {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	taps, ok := t.taps[channel]
+
+	if !ok {
+		return false
+	}
+
+	for ch := range taps {
+		select {
+		case ch <- append([]byte(nil), data...):
+		default:
+		}
+	}
+
+	return true
+}
+
+// Tap subscribes to the channel and streams its raw messages to the returned channel until the context is done
+// (the returned channel is closed then). Messages from the channels not subscribed otherwise are not passed to the node.
+// Messages are skipped if the reader is too slow.
+//
+// The subscription connection is used by default (the channel is subscribed to along with the configured ones);
+// a separate connection per tap is used if the TapConnection option is enabled.
+// In sharded mode, the channel must belong to the same hash slot as the broadcasts channel.
+// Intended for debugging and admin tools (e.g., to watch the broadcasts published to a specific channel).
+func (s *RedisSubscriber) Tap(ctx context.Context, channel string) (<-chan []byte, error) {
+	if channel == "" {
+		return nil, errors.New("channel is required")
+	}
+
+	if s.stopped() {
+		return nil, errors.New("subscriber is stopped")
+	}
+
+	// Both the subscription and tap connections are connected to the shard owning the broadcasts channel
+	if err := s.checkSlot(channel); err != nil {
+		return nil, err
+	}
+
+	if s.tapConnection {
+		return s.tapViaConnection(ctx, channel)
+	}
+
+	ch := make(chan []byte, redisTapBufferSize)
+
+	s.taps.add(channel, ch)
+	s.notifyChannelsChanged()
+
+	s.logger().Infof("Tapped Redis channel %s", channel)
+
+	go func() {
+		select {
+		case <-ctx.Done():
+		case <-s.shutdownCh:
+		}
+
+		s.taps.remove(channel, ch)
+		s.notifyChannelsChanged()
+
+		s.logger().Infof("Untapped Redis channel %s", channel)
+	}()
+
+	return ch, nil
+}
+
+// tapViaConnection subscribes to the channel via a separate connection;
+// the tap is closed if the connection fails (no reconnects are made)
+func (s *RedisSubscriber) tapViaConnection(ctx context.Context, channel string) (<-chan []byte, error) {
+	dialOptions := append(s.dialOptions(), s.clientNameOptions(redisClientNameTap)...)
+
+	c, err := redis.DialURL(s.currentURL(), dialOptions...)
+
+	if err != nil {
+		return nil, err
+	}
+
+	psc := redis.PubSubConn{Conn: c}
+
+	// SSUBSCRIBE is used in sharded mode
+	if err = c.Send(s.subscriptions.command, channel); err == nil {
+		err = c.Flush()
+	}
+
+	if err != nil {
+		c.Close()
+		return nil, err
+	}
+
+	ch := make(chan []byte, redisTapBufferSize)
+
+	s.logger().Infof("Tapped Redis channel %s via a separate connection", channel)
+
+	go func() {
+		select {
+		case <-ctx.Done():
+		case <-s.shutdownCh:
+		}
+
+		// Closing the connection interrupts receiving
+		c.Close()
+	}()
+
+	go func() {
+		defer close(ch)
+
+		for {
+			switch v := s.receiveReply(psc).(type) {
+			case redis.Message:
+				select {
+				case ch <- v.Data:
+				default:
+				}
+			case error:
+				if ctx.Err() == nil && !s.stopped() {
+					s.logger().Warnf("Redis tap connection for channel %s failed: %v", channel, v)
+				} else {
+					s.logger().Infof("Untapped Redis channel %s", channel)
+				}
+
+				return
+			}
+		}
+	}()
+
+	return ch, nil
+}
+
+// tapMessage passes the message to the taps; returns true if the message must not be processed further
+// (i.e., the channel is only subscribed to because of the taps)
+func (s *RedisSubscriber) tapMessage(channel string, data []byte) bool {
+	if !s.taps.send(channel, data) {
+		return false
+	}
+
+	return !containsString(s.regularChannels(), channel)
+}
+
+func containsString(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,150 @@
+package pubsub
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/anycable/anycable-go/metrics"
+	"github.com/anycable/anycable-go/pubsub/logging"
+)
+
+const (
+	// The max number of messages waiting to be written to the tee file.
+	// Messages are skipped if the buffer is full (i.e., the writer is too slow).
+	redisTeeBufferSize = 1000
+	// How often to flush the tee file buffer
+	redisTeeFlushInterval = time.Second
+)
+
+// redisTeeConfig contains the destinations to copy received messages to
+type redisTeeConfig struct {
+	// File to append messages to (as JSON lines)
+	Path string
+	// Unix socket to stream messages to (see teeSocketRecord for the framing)
+	Socket string
+}
+
+type teeRecord struct {
+	Channel string `json:"channel"`
+	Data    string `json:"data"`
+	At      int64  `json:"at"`
+}
+
+// redisTee copies received messages to a file and/or a Unix socket (e.g., for debugging or sidecars).
+// Messages are written in the background, so a slow destination never blocks processing.
+type redisTee struct {
+	config  redisTeeConfig
+	metrics metrics.Instrumenter
+	logger  func() logging.Logger
+
+	file     *os.File
+	fileCh   chan *teeRecord
+	socketCh chan *teeSocketRecord
+
+	wg       sync.WaitGroup
+	stopOnce sync.Once
+	stopCh   chan struct{}
+}
+
+func newRedisTee(config redisTeeConfig, metrics metrics.Instrumenter, logger func() logging.Logger) *redisTee {
+	return &redisTee{config: config, metrics: metrics, logger: logger, stopCh: make(chan struct{})}
+}
+
+// Enabled returns true if any destination is configured
+func (t *redisTee) Enabled() bool {
+	return t.config.Path != "" || t.config.Socket != ""
+}
+
+// Open opens the tee file (if configured), so misconfiguration is reported before starting
+func (t *redisTee) Open() error {
+	if t.config.Path == "" {
+		return nil
+	}
+
+	f, err := os.OpenFile(t.config.Path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+
+	if err != nil {
+		return fmt.Errorf("failed to open tee file: %w", err)
+	}
+
+	t.file = f
+
+	return nil
+}
+
+// Start starts writing messages to the configured destinations (the file must be opened first)
+func (t *redisTee) Start() {
+	if t.file != nil {
+		t.logger().Infof("Received Redis messages are written to %s", t.config.Path)
+
+		t.fileCh = make(chan *teeRecord, redisTeeBufferSize)
+		t.wg.Add(1)
+		go t.writeFile(t.file)
+	}
+
+	if t.config.Socket != "" {
+		t.logger().Infof("Received Redis messages are written to socket %s", t.config.Socket)
+
+		t.socketCh = make(chan *teeSocketRecord, redisTeeSocketBufferSize)
+		t.wg.Add(1)
+		go t.writeSocket()
+	}
+}
+
+// Stop stops the writers (buffered messages not written yet are discarded)
+func (t *redisTee) Stop() {
+	t.stopOnce.Do(func() { close(t.stopCh) })
+	t.wg.Wait()
+}
+
+// Send passes the received message to the writers without blocking
+func (t *redisTee) Send(channel string, data []byte) {
+	if t.fileCh != nil {
+		select {
+		case t.fileCh <- &teeRecord{Channel: channel, Data: string(data), At: time.Now().UnixMilli()}:
+		default:
+			t.logger().Debug("Tee buffer is full, message is skipped")
+		}
+	}
+
+	if t.socketCh != nil {
+		select {
+		case t.socketCh <- &teeSocketRecord{channel: channel, data: data}:
+		default:
+			t.metrics.CounterIncrement(metricsRedisTeeSocketDropped)
+		}
+	}
+}
+
+// writeFile appends messages (as JSON lines) to the tee file until stopped
+func (t *redisTee) writeFile(f *os.File) {
+	defer t.wg.Done()
+	defer f.Close()
+
+	w := bufio.NewWriter(f)
+	defer w.Flush() // nolint:errcheck
+
+	encoder := json.NewEncoder(w)
+
+	ticker := time.NewTicker(redisTeeFlushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-t.stopCh:
+			return
+		case <-ticker.C:
+			if err := w.Flush(); err != nil {
+				t.logger().Warnf("Failed to write to tee file: %v", err)
+			}
+		case record := <-t.fileCh:
+			if err := encoder.Encode(record); err != nil {
+				t.logger().Warnf("Failed to write to tee file: %v", err)
+			}
+		}
+	}
+}
@@ -0,0 +1,105 @@
+package pubsub
+
+import (
+	"encoding/binary"
+	"net"
+	"time"
+)
+
+const (
+	// The max number of messages waiting to be written to the tee socket.
+	// Messages are dropped if the buffer is full (i.e., the sidecar is too slow).
+	redisTeeSocketBufferSize = 1000
+	// How long to wait before re-dialing the tee socket after a failure (messages are dropped meanwhile)
+	redisTeeSocketRetryInterval = 5 * time.Second
+	// The max time to dial the tee socket or to write a message to it
+	redisTeeSocketTimeout = time.Second
+
+	metricsRedisTeeSocketDropped = "redis_tee_socket_dropped_total"
+)
+
+type teeSocketRecord struct {
+	channel string
+	data    []byte
+}
+
+// encode returns the record framed as the channel and the payload, each prefixed with its length
+// (4-byte big-endian unsigned integer)
+func (r *teeSocketRecord) encode() []byte {
+	buf := make([]byte, 8+len(r.channel)+len(r.data))
+
+	binary.BigEndian.PutUint32(buf, uint32(len(r.channel)))
+	copy(buf[4:], r.channel)
+
+	offset := 4 + len(r.channel)
+
+	binary.BigEndian.PutUint32(buf[offset:], uint32(len(r.data)))
+	copy(buf[offset+4:], r.data)
+
+	return buf
+}
+
+// writeSocket writes messages to the Unix socket until stopped.
+// The socket is dialed lazily and re-dialed after failures; messages are dropped while it's unavailable.
+func (t *redisTee) writeSocket() {
+	defer t.wg.Done()
+
+	var conn net.Conn
+	var retryAt time.Time
+
+	failing := false
+
+	defer func() {
+		if conn != nil {
+			conn.Close()
+		}
+	}()
+
+	fail := func(format string, err error) {
+		if failing {
+			t.logger().Debugf(format, t.config.Socket, err)
+		} else {
+			t.logger().Warnf(format, t.config.Socket, err)
+		}
+
+		failing = true
+		retryAt = time.Now().Add(redisTeeSocketRetryInterval)
+	}
+
+	for {
+		select {
+		case <-t.stopCh:
+			return
+		case record := <-t.socketCh:
+			if conn == nil {
+				if time.Now().Before(retryAt) {
+					t.metrics.CounterIncrement(metricsRedisTeeSocketDropped)
+					continue
+				}
+
+				c, err := net.DialTimeout("unix", t.config.Socket, redisTeeSocketTimeout)
+
+				if err != nil {
+					fail("Failed to connect to tee socket %s: %v", err)
+					t.metrics.CounterIncrement(metricsRedisTeeSocketDropped)
+					continue
+				}
+
+				conn = c
+				failing = false
+
+				t.logger().Infof("Connected to tee socket %s", t.config.Socket)
+			}
+
+			conn.SetWriteDeadline(time.Now().Add(redisTeeSocketTimeout)) // nolint:errcheck
+
+			if _, err := conn.Write(record.encode()); err != nil {
+				fail("Failed to write to tee socket %s: %v", err)
+				t.metrics.CounterIncrement(metricsRedisTeeSocketDropped)
+
+				conn.Close()
+				conn = nil
+			}
+		}
+	}
+}
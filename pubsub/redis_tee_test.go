@@ -0,0 +1,157 @@
+package pubsub
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/anycable/anycable-go/metrics"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRedisTee(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tee.log")
+
+	tee := newRedisTee(redisTeeConfig{Path: path}, metrics.NoopMetrics{}, testRedisLogger())
+
+	assert.True(t, tee.Enabled())
+	require.NoError(t, tee.Open())
+
+	tee.Start()
+
+	tee.Send("__anycable__", []byte(`{"stream":"chat"}`))
+	tee.Send("__anycable__", []byte(`{"stream":"chat"}`))
+
+	assert.Eventually(t, func() bool { return len(tee.fileCh) == 0 }, time.Second, 10*time.Millisecond)
+
+	tee.Stop()
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	require.Len(t, lines, 2)
+
+	var record teeRecord
+
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &record))
+	assert.Equal(t, "__anycable__", record.Channel)
+	assert.Equal(t, `{"stream":"chat"}`, record.Data)
+
+	t.Run("Doesn't block when the buffer is full", func(t *testing.T) {
+		tee := newRedisTee(redisTeeConfig{Path: path}, metrics.NoopMetrics{}, testRedisLogger())
+		tee.fileCh = make(chan *teeRecord, 1)
+
+		tee.Send("__anycable__", []byte("one"))
+		tee.Send("__anycable__", []byte("two"))
+
+		assert.Equal(t, "one", (<-tee.fileCh).Data)
+	})
+
+	t.Run("Fails to open the file", func(t *testing.T) {
+		tee := newRedisTee(redisTeeConfig{Path: filepath.Join(t.TempDir(), "missing", "tee.log")}, metrics.NoopMetrics{}, testRedisLogger())
+
+		assert.Error(t, tee.Open())
+	})
+
+	t.Run("Disabled", func(t *testing.T) {
+		tee := newRedisTee(redisTeeConfig{}, metrics.NoopMetrics{}, testRedisLogger())
+
+		assert.False(t, tee.Enabled())
+		require.NoError(t, tee.Open())
+
+		tee.Send("__anycable__", []byte("one"))
+		tee.Stop()
+	})
+}
+
+func TestRedisTeeSocket(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tee.sock")
+
+	listener, err := net.Listen("unix", path)
+	require.NoError(t, err)
+	defer listener.Close()
+
+	m := metrics.NewMetrics(nil, 0)
+	m.RegisterCounter(metricsRedisTeeSocketDropped, "")
+
+	tee := newRedisTee(redisTeeConfig{Socket: path}, m, testRedisLogger())
+
+	assert.True(t, tee.Enabled())
+	tee.Start()
+
+	tee.Send("__anycable__", []byte(`{"stream":"chat"}`))
+	tee.Send("__anycable__", []byte(`{"stream":"chat"}`))
+
+	sidecar, err := listener.Accept()
+	require.NoError(t, err)
+	defer sidecar.Close()
+
+	readFrame := func() string {
+		size := make([]byte, 4)
+
+		_, err := io.ReadFull(sidecar, size)
+		require.NoError(t, err)
+
+		buf := make([]byte, binary.BigEndian.Uint32(size))
+
+		_, err = io.ReadFull(sidecar, buf)
+		require.NoError(t, err)
+
+		return string(buf)
+	}
+
+	for i := 0; i < 2; i++ {
+		assert.Equal(t, "__anycable__", readFrame())
+		assert.Equal(t, `{"stream":"chat"}`, readFrame())
+	}
+
+	tee.Stop()
+
+	assert.Equal(t, uint64(0), m.Counter(metricsRedisTeeSocketDropped).Value())
+
+	t.Run("Drops messages when the buffer is full", func(t *testing.T) {
+		m := metrics.NewMetrics(nil, 0)
+		m.RegisterCounter(metricsRedisTeeSocketDropped, "")
+
+		tee := newRedisTee(redisTeeConfig{Socket: path}, m, testRedisLogger())
+		tee.socketCh = make(chan *teeSocketRecord, 1)
+
+		tee.Send("__anycable__", []byte("one"))
+		tee.Send("__anycable__", []byte("two"))
+
+		assert.Equal(t, "one", string((<-tee.socketCh).data))
+		assert.Equal(t, uint64(1), m.Counter(metricsRedisTeeSocketDropped).Value())
+	})
+
+	t.Run("Drops messages when the socket is unavailable", func(t *testing.T) {
+		m := metrics.NewMetrics(nil, 0)
+		m.RegisterCounter(metricsRedisTeeSocketDropped, "")
+
+		tee := newRedisTee(redisTeeConfig{Socket: filepath.Join(t.TempDir(), "missing.sock")}, m, testRedisLogger())
+
+		tee.Start()
+
+		tee.Send("__anycable__", []byte("one"))
+		tee.Send("__anycable__", []byte("two"))
+
+		assert.Eventually(t, func() bool {
+			return m.Counter(metricsRedisTeeSocketDropped).Value() == 2
+		}, time.Second, 10*time.Millisecond)
+
+		tee.Stop()
+	})
+}
+
+func TestTeeSocketRecordEncode(t *testing.T) {
+	record := &teeSocketRecord{channel: "ch", data: []byte("data")}
+
+	assert.Equal(t, []byte("\x00\x00\x00\x02ch\x00\x00\x00\x04data"), record.encode())
+}
@@ -0,0 +1,27 @@
+package pubsub
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// warmingUp returns true if the subscriber has been started less than the warmup period ago.
+// During warmup, connection failures are expected (e.g., Redis is booting along with the server),
+// so they're logged at a lower level and don't count toward the max reconnect attempts
+func (s *RedisSubscriber) warmingUp() bool {
+	return s.warmupPeriod > 0 && time.Since(s.totals.startedAt) < s.warmupPeriod
+}
+
+// nextAttempt returns the reconnect attempt number to calculate the delay from.
+// Warmup attempts are tracked separately and capped, so the backoff is bounded and the subscriber never gives up
+func (s *RedisSubscriber) nextAttempt(warmupAttempts *int32) int32 {
+	if !s.warmingUp() {
+		return atomic.AddInt32(&s.reconnectAttempt, 1)
+	}
+
+	if *warmupAttempts < maxReconnectAttempts-1 {
+		*warmupAttempts++
+	}
+
+	return *warmupAttempts
+}
@@ -0,0 +1,119 @@
+package pubsub
+
+import (
+	"errors"
+	"net"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/anycable/anycable-go/pubsub/logging"
+)
+
+const (
+	sdNotifyWatchdog = "WATCHDOG=1"
+)
+
+var errSystemdWatchdogDisabled = errors.New("systemd watchdog is not enabled for the process (WATCHDOG_USEC is not set)")
+
+// systemdWatchdogInterval returns the watchdog timeout configured by systemd for the process
+// (WatchdogSec= in the unit file)
+func systemdWatchdogInterval() (time.Duration, error) {
+	if os.Getenv("NOTIFY_SOCKET") == "" || os.Getenv("WATCHDOG_USEC") == "" {
+		return 0, errSystemdWatchdogDisabled
+	}
+
+	if pid := os.Getenv("WATCHDOG_PID"); pid != "" && pid != strconv.Itoa(os.Getpid()) {
+		return 0, errSystemdWatchdogDisabled
+	}
+
+	usec, err := strconv.ParseInt(os.Getenv("WATCHDOG_USEC"), 10, 64)
+
+	if err != nil || usec <= 0 {
+		return 0, errors.New("invalid WATCHDOG_USEC value")
+	}
+
+	return time.Duration(usec) * time.Microsecond, nil
+}
+
+// sdNotify sends the state to the systemd notification socket (see sd_notify(3))
+func sdNotify(state string) error {
+	addr := os.Getenv("NOTIFY_SOCKET")
+
+	// Abstract namespace socket
+	if addr != "" && addr[0] == '@' {
+		addr = "\x00" + addr[1:]
+	}
+
+	conn, err := net.DialUnix("unixgram", nil, &net.UnixAddr{Name: addr, Net: "unixgram"})
+
+	if err != nil {
+		return err
+	}
+
+	defer conn.Close()
+
+	_, err = conn.Write([]byte(state))
+
+	return err
+}
+
+// redisWatchdogConfig contains the systemd watchdog settings
+type redisWatchdogConfig struct {
+	// The watchdog timeout configured by systemd (pings are sent twice per interval)
+	Interval time.Duration
+}
+
+// redisWatchdog pings the systemd watchdog while the subscriber is live,
+// so systemd restarts the process if the subscriber is wedged or has given up
+type redisWatchdog struct {
+	config redisWatchdogConfig
+	live   func() bool
+	logger func() logging.Logger
+
+	wg       sync.WaitGroup
+	stopOnce sync.Once
+	stopCh   chan struct{}
+}
+
+func newRedisWatchdog(config redisWatchdogConfig, live func() bool, logger func() logging.Logger) *redisWatchdog {
+	return &redisWatchdog{config: config, live: live, logger: logger, stopCh: make(chan struct{})}
+}
+
+// Start starts pinging the watchdog
+func (w *redisWatchdog) Start() {
+	w.logger().Infof("Redis subscriber pings systemd watchdog every %s", w.config.Interval/2)
+
+	w.wg.Add(1)
+	go w.run()
+}
+
+// Stop stops pinging the watchdog
+func (w *redisWatchdog) Stop() {
+	w.stopOnce.Do(func() { close(w.stopCh) })
+	w.wg.Wait()
+}
+
+func (w *redisWatchdog) run() {
+	defer w.wg.Done()
+
+	ticker := time.NewTicker(w.config.Interval / 2)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-w.stopCh:
+			return
+		case <-ticker.C:
+			if !w.live() {
+				w.logger().Debug("Redis subscriber is not live, skipping systemd watchdog ping")
+				continue
+			}
+
+			if err := sdNotify(sdNotifyWatchdog); err != nil {
+				w.logger().Debugf("Failed to ping systemd watchdog: %v", err)
+			}
+		}
+	}
+}
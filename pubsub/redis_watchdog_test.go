@@ -0,0 +1,121 @@
+package pubsub
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/anycable/anycable-go/metrics"
+	"github.com/anycable/anycable-go/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func listenSystemdNotify(t *testing.T) *net.UnixConn {
+	dir, err := os.MkdirTemp("", "sd")
+	require.NoError(t, err)
+
+	addr := filepath.Join(dir, "notify.sock")
+
+	sock, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: addr, Net: "unixgram"})
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		sock.Close()
+		os.RemoveAll(dir)
+	})
+	t.Setenv("NOTIFY_SOCKET", addr)
+
+	return sock
+}
+
+func TestSystemdWatchdogInterval(t *testing.T) {
+	t.Setenv("NOTIFY_SOCKET", "")
+	t.Setenv("WATCHDOG_USEC", "")
+
+	_, err := systemdWatchdogInterval()
+	assert.ErrorIs(t, err, errSystemdWatchdogDisabled)
+
+	t.Setenv("NOTIFY_SOCKET", "/run/systemd/notify")
+	t.Setenv("WATCHDOG_USEC", "20000")
+
+	interval, err := systemdWatchdogInterval()
+	require.NoError(t, err)
+	assert.Equal(t, 20*time.Millisecond, interval)
+
+	t.Setenv("WATCHDOG_USEC", "invalid")
+
+	_, err = systemdWatchdogInterval()
+	assert.Error(t, err)
+}
+
+func TestRedisWatchdog(t *testing.T) {
+	sock := listenSystemdNotify(t)
+
+	var live int32 = 1
+
+	watchdog := newRedisWatchdog(
+		redisWatchdogConfig{Interval: 20 * time.Millisecond},
+		func() bool { return atomic.LoadInt32(&live) == 1 },
+		testRedisLogger(),
+	)
+
+	watchdog.Start()
+	defer watchdog.Stop()
+
+	buf := make([]byte, 64)
+	require.NoError(t, sock.SetReadDeadline(time.Now().Add(time.Second)))
+
+	n, err := sock.Read(buf)
+	require.NoError(t, err)
+	assert.Equal(t, "WATCHDOG=1", string(buf[:n]))
+
+	t.Run("Doesn't ping when not live", func(t *testing.T) {
+		atomic.StoreInt32(&live, 0)
+
+		// Drain the pings sent before the state has changed
+		time.Sleep(20 * time.Millisecond)
+
+		for {
+			require.NoError(t, sock.SetReadDeadline(time.Now().Add(50*time.Millisecond)))
+
+			if _, err := sock.Read(buf); err != nil {
+				break
+			}
+		}
+
+		require.NoError(t, sock.SetReadDeadline(time.Now().Add(100*time.Millisecond)))
+
+		_, err := sock.Read(buf)
+		assert.Error(t, err)
+	})
+}
+
+func TestRedisSystemdWatchdog(t *testing.T) {
+	sock := listenSystemdNotify(t)
+
+	t.Setenv("WATCHDOG_USEC", "20000")
+
+	config := NewRedisConfig()
+	config.SystemdWatchdog = true
+
+	subscriber := NewRedisSubscriber(&mocks.Handler{}, metrics.NoopMetrics{}, &config)
+	subscriber.connect = func() error {
+		<-subscriber.shutdownCh
+		return nil
+	}
+
+	done := make(chan error, 1)
+	require.NoError(t, subscriber.Start(done))
+	defer subscriber.Shutdown() // nolint:errcheck
+
+	buf := make([]byte, 64)
+	require.NoError(t, sock.SetReadDeadline(time.Now().Add(time.Second)))
+
+	n, err := sock.Read(buf)
+	require.NoError(t, err)
+	assert.Equal(t, "WATCHDOG=1", string(buf[:n]))
+}
@@ -2,24 +2,74 @@ package pubsub
 
 import (
 	"fmt"
+	"time"
+
+	"github.com/anycable/anycable-go/metrics"
 )
 
 // Subscriber is responsible for receiving broadcast messages
-// and sending them to hub
+// and sending them to hub.
+// Subscriber must be shut down before the handler (node).
 type Subscriber interface {
 	Start(done chan (error)) error
 	Shutdown() error
 }
 
+// Handler processes broadcast messages received by subscribers (e.g., node.Node).
+// Subscribers depend only on this interface, so any implementation (e.g., a test fake) could be used.
 type Handler interface {
 	HandlePubSub(json []byte)
 }
 
+// CommandHandler could be implemented by handlers to process control messages
+// (e.g., remote disconnects) separately from broadcasts
+type CommandHandler interface {
+	HandlePubSubCommand(json []byte)
+}
+
+// CheckedHandler could be implemented by handlers to report broadcast processing failures;
+// failures wrapped with RetryableError are re-attempted (see RedisConfig.DispatchRetries).
+// It takes precedence over MetaHandler.
+type CheckedHandler interface {
+	HandlePubSubChecked(json []byte) error
+}
+
+// MetaHandler could be implemented by handlers to receive broadcasts
+// along with the source channel and the time the message was received
+type MetaHandler interface {
+	HandlePubSubWithMeta(channel string, json []byte, receivedAt time.Time)
+}
+
+// ReadyNotifier could be implemented by handlers to notify subscribers
+// when they are ready to process broadcasts
+type ReadyNotifier interface {
+	Ready() <-chan struct{}
+}
+
+// KeyspaceHandler could be implemented by handlers to receive Redis keyspace notifications
+// (e.g., "expired" events) with the name of the affected key
+type KeyspaceHandler interface {
+	HandleKeyspaceEvent(event string, key string)
+}
+
+// PressureReporter could be implemented by handlers to report how busy they are
+// (from 0, idle, to 1, overloaded), so subscribers could shed load or slow down reading
+// before the handler is overwhelmed
+type PressureReporter interface {
+	Pressure() float64
+}
+
 // NewSubscriber creates an instance of the provided adapter
-func NewSubscriber(node Handler, adapter string, redis *RedisConfig, http *HTTPConfig, nats *NATSConfig) (Subscriber, error) {
+func NewSubscriber(node Handler, metrics metrics.Instrumenter, adapter string, redis *RedisConfig, http *HTTPConfig, nats *NATSConfig) (Subscriber, error) {
 	switch adapter {
 	case "redis":
-		return NewRedisSubscriber(node, redis), nil
+		if redis.CommandsConnection {
+			return NewRedisSplitSubscriber(node, metrics, redis)
+		}
+
+		return NewRedisSubscriber(node, metrics, redis), nil
+	case "redis_queue":
+		return NewRedisQueueSubscriber(node, metrics, redis), nil
 	case "http":
 		return NewHTTPSubscriber(node, http), nil
 	case "nats":
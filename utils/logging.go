@@ -38,3 +38,13 @@ func InitLogger(format string, level string) error {
 
 	return nil
 }
+
+// IsDebug returns true if debug logging is enabled.
+// Use it to avoid formatting debug messages on hot paths.
+func IsDebug() bool {
+	if logger, ok := log.Log.(*log.Logger); ok {
+		return logger.Level <= log.DebugLevel
+	}
+
+	return true
+}
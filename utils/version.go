@@ -0,0 +1,73 @@
+package utils
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Version is a semantic-like version (major.minor.patch); missing parts are zeros
+type Version struct {
+	Major int
+	Minor int
+	Patch int
+}
+
+// ParseVersion parses a version string such as "7.2.4", "6.0" or "v1.2.3-rc1"
+// (the "v" prefix and pre-release/build suffixes are ignored)
+func ParseVersion(raw string) (Version, error) {
+	var version Version
+
+	str := strings.TrimPrefix(strings.TrimSpace(raw), "v")
+
+	if idx := strings.IndexAny(str, "-+ "); idx >= 0 {
+		str = str[:idx]
+	}
+
+	parts := strings.Split(str, ".")
+
+	if str == "" || len(parts) > 3 {
+		return version, fmt.Errorf("malformed version: %s", raw)
+	}
+
+	fields := []*int{&version.Major, &version.Minor, &version.Patch}
+
+	for i, part := range parts {
+		num, err := strconv.Atoi(part)
+
+		if err != nil || num < 0 {
+			return version, fmt.Errorf("malformed version: %s", raw)
+		}
+
+		*fields[i] = num
+	}
+
+	return version, nil
+}
+
+// Compare returns -1 if the version is older than the other one, 1 if it's newer and 0 if they're equal
+func (v Version) Compare(other Version) int {
+	switch {
+	case v.Major != other.Major:
+		return compareInts(v.Major, other.Major)
+	case v.Minor != other.Minor:
+		return compareInts(v.Minor, other.Minor)
+	default:
+		return compareInts(v.Patch, other.Patch)
+	}
+}
+
+func (v Version) String() string {
+	return fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+}
+
+func compareInts(a int, b int) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
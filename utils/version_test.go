@@ -0,0 +1,41 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseVersion(t *testing.T) {
+	examples := map[string]Version{
+		"7.2.4":      {Major: 7, Minor: 2, Patch: 4},
+		"6.0":        {Major: 6},
+		"5":          {Major: 5},
+		"v1.2.3-rc1": {Major: 1, Minor: 2, Patch: 3},
+		" 6.2.7\r":   {Major: 6, Minor: 2, Patch: 7},
+	}
+
+	for raw, expected := range examples {
+		version, err := ParseVersion(raw)
+
+		require.NoError(t, err, raw)
+		assert.Equal(t, expected, version, raw)
+	}
+
+	for _, raw := range []string{"", "unknown", "6.x", "1.2.3.4", "-1.0"} {
+		_, err := ParseVersion(raw)
+
+		assert.Error(t, err, raw)
+	}
+}
+
+func TestVersionCompare(t *testing.T) {
+	v := Version{Major: 6, Minor: 2, Patch: 7}
+
+	assert.Equal(t, 0, v.Compare(Version{Major: 6, Minor: 2, Patch: 7}))
+	assert.Equal(t, 1, v.Compare(Version{Major: 6, Minor: 2}))
+	assert.Equal(t, 1, v.Compare(Version{Major: 5, Minor: 9, Patch: 9}))
+	assert.Equal(t, -1, v.Compare(Version{Major: 6, Minor: 10}))
+	assert.Equal(t, -1, v.Compare(Version{Major: 7}))
+}